@@ -0,0 +1,194 @@
+package android
+
+import (
+	"encoding/xml"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.gilang.dev/translator/v2/internal/placeholder"
+)
+
+// escape matches Android escape sequences.
+var escape = regexp.MustCompile(`\\u[0-9A-Fa-f]{4}|\\.`)
+
+// sequence matches, in the text of a value, Android escape sequences, format
+// specifiers and HTML tags, such as those of CDATA sections.
+var sequence = regexp.MustCompile(`\\u[0-9A-Fa-f]{4}|\\.|` + placeholder.Printf.String() + `|</?[A-Za-z][^<>]*>|"`)
+
+// content is the content of a value, split into the text to translate,
+// with placeholders, and the markup of the placeholders.
+type content struct {
+	text   string
+	tokens []string
+	plains []string           // Text of each placeholder in the plain text
+	pairs  []placeholder.Pair // Placeholders of start and end tags
+	quoted bool               // Whether the value is wrapped in double quotes
+}
+
+// parseContent parses the markup of a value. Escape sequences are unescaped,
+// except those of line breaks, tabs and Unicode characters which are
+// protected with format specifiers, tags and <xliff:g> elements.
+func parseContent(markup string) *content {
+	c := &content{}
+	var (
+		b    strings.Builder
+		tags []string // Tag of each placeholder, if any, to pair them
+	)
+	protect := func(markup, plain string) {
+		b.WriteString(placeholder.Token(len(c.tokens)))
+		c.tokens = append(c.tokens, markup)
+		c.plains = append(c.plains, plain)
+		tags = append(tags, "")
+	}
+	protectTag := func(tag, markup string) {
+		protect(markup, "")
+		tags[len(tags)-1] = tag
+	}
+
+	const wrapper = "value"
+	dec := xml.NewDecoder(strings.NewReader("<" + wrapper + ">" + markup + "</" + wrapper + ">"))
+	dec.Strict = false
+	offset := len(wrapper) + 2
+	var (
+		code       strings.Builder // Markup of the <xliff:g> being read
+		codePlain  strings.Builder
+		codeDepth  int
+		wrapperEnd = offset + len(markup)
+	)
+	for {
+		start := int(dec.InputOffset())
+		token, err := dec.Token()
+		if err != nil {
+			break
+		}
+		end := int(dec.InputOffset())
+		if start < offset || end > wrapperEnd {
+			continue
+		}
+		raw := markup[start-offset : end-offset]
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch {
+			case codeDepth > 0:
+				code.WriteString(raw)
+				codeDepth++
+			case t.Name.Local == "g":
+				code.WriteString(raw)
+				codeDepth = 1
+			default:
+				protectTag(raw, raw)
+			}
+		case xml.EndElement:
+			switch {
+			case codeDepth > 0:
+				code.WriteString(raw)
+				if codeDepth--; codeDepth == 0 {
+					protect(code.String(), codePlain.String())
+					code.Reset()
+					codePlain.Reset()
+				}
+			case raw != "":
+				protectTag(raw, raw)
+			}
+		case xml.CharData:
+			if codeDepth > 0 {
+				code.WriteString(raw)
+				codePlain.WriteString(unescape(string(t)))
+				continue
+			}
+			c.readText(string(t), &b, protect, protectTag)
+		default:
+			if codeDepth > 0 {
+				code.WriteString(raw)
+				continue
+			}
+			protect(raw, "")
+		}
+	}
+	c.text = b.String()
+	c.pairs = placeholder.TagPairs(tags)
+	return c
+}
+
+// readText appends text, unescaped from XML, to b, protecting its escape
+// sequences, format specifiers and tags, and dropping its quotes.
+func (c *content) readText(text string, b *strings.Builder, protect func(markup, plain string), protectTag func(tag, markup string)) {
+	last := 0
+	for _, match := range sequence.FindAllStringIndex(text, -1) {
+		b.WriteString(text[last:match[0]])
+		last = match[1]
+		s := text[match[0]:match[1]]
+		switch {
+		case s == `"`:
+			c.quoted = true
+		case s == `\n` || s == `\t` || s == `\r` || strings.HasPrefix(s, `\u`):
+			protect(escapeXML(s), unescape(s))
+		case strings.HasPrefix(s, `\`):
+			b.WriteString(s[1:])
+		case strings.HasPrefix(s, "<"):
+			protectTag(s, escapeXML(s))
+		default:
+			protect(escapeXML(s), s)
+		}
+	}
+	b.WriteString(text[last:])
+}
+
+// intact tells whether translation, a translation of the text of c, keeps
+// each placeholder once and the tags balanced, so that its markup is
+// well-formed.
+func (c *content) intact(translation string) bool {
+	return placeholder.Intact(translation, len(c.tokens), c.pairs)
+}
+
+// plain returns text, the text of c or of a translation, without markup.
+func (c *content) plain(text string) string {
+	return placeholder.Restore(text, c.plains)
+}
+
+// markup returns the markup of a translation of c.
+func (c *content) markup(translation string) string {
+	markup := placeholder.Restore(escapeText(translation, c.quoted), c.tokens)
+	if c.quoted {
+		return `"` + markup + `"`
+	}
+	if strings.HasPrefix(markup, "@") || strings.HasPrefix(markup, "?") {
+		markup = `\` + markup
+	}
+	return markup
+}
+
+// escapeText escapes text for a value: backslashes, quotes, and apostrophes
+// outside quoted values, then XML special characters.
+func escapeText(text string, quoted bool) string {
+	text = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`).Replace(text)
+	if !quoted {
+		text = strings.ReplaceAll(text, "'", `\'`)
+	}
+	return escapeXML(text)
+}
+
+func escapeXML(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// unescape unescapes Android escape sequences.
+func unescape(s string) string {
+	return escape.ReplaceAllStringFunc(s, func(sequence string) string {
+		switch sequence[1] {
+		case 'n':
+			return "\n"
+		case 't':
+			return "\t"
+		case 'r':
+			return "\r"
+		case 'u':
+			if r, err := strconv.ParseUint(sequence[2:], 16, 32); err == nil && len(sequence) == 6 {
+				return string(rune(r))
+			}
+		}
+		return sequence[1:]
+	})
+}
@@ -0,0 +1,197 @@
+// Package android reads, writes and translates Android string resources
+// (res/values/strings.xml): strings, string arrays and plurals.
+package android
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Resource types holding texts.
+const (
+	TypeString      = "string"
+	TypeStringArray = "string-array"
+	TypePlurals     = "plurals"
+)
+
+// Document is a string resources file.
+type Document struct {
+	Resources []*Resource
+
+	head, tail string // Markup before the first and after the last resource
+}
+
+// Resource is an element of a resources file: a string, string array or
+// plurals, or any other resource, such as a dimen, which has no values.
+type Resource struct {
+	Type         string
+	Name         string
+	Translatable bool     // False for translatable="false" and other types
+	Values       []*Value // A single value for strings
+
+	prefix   string // Whitespace and comments before the element
+	startTag string
+	suffix   string // Markup between the last item and the end tag
+	endTag   string
+	raw      string // Element of other types
+}
+
+// Value is a string, or an item of a string array or plurals.
+type Value struct {
+	Quantity string // Plural category of a plurals item
+	Text     string // Text, unescaped and without markup
+
+	prefix   string
+	startTag string
+	markup   string // Content of the element
+}
+
+// Parse parses a string resources file.
+func Parse(data []byte) (*Document, error) {
+	d := &Document{}
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var (
+		depth    int
+		last     int // End of the last resource or item
+		begin    int // Start of the resource being read
+		content  int // Start of the content of the value being read
+		resource *Resource
+		value    *Value
+	)
+	for {
+		start := int(dec.InputOffset())
+		token, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("android: %w", err)
+		}
+		end := int(dec.InputOffset())
+		raw := string(data[start:end])
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			switch {
+			case depth == 1:
+				if t.Name.Local != "resources" {
+					return nil, errors.New("android: not a resources file")
+				}
+				d.head, last = string(data[:end]), end
+			case depth == 2:
+				name, _ := attr(t, "name")
+				translatable, _ := attr(t, "translatable")
+				resource = &Resource{
+					Type:         t.Name.Local,
+					Name:         name,
+					Translatable: translatable != "false",
+					prefix:       string(data[last:start]),
+					startTag:     raw,
+				}
+				switch resource.Type {
+				case TypeString:
+					value, content = &Value{}, end
+				case TypeStringArray, TypePlurals:
+				default:
+					resource.Translatable = false
+				}
+				begin, last = start, end
+			case depth == 3 && t.Name.Local == "item" && (resource.Type == TypeStringArray || resource.Type == TypePlurals):
+				quantity, _ := attr(t, "quantity")
+				value = &Value{Quantity: quantity, prefix: string(data[last:start]), startTag: raw}
+				content = end
+			}
+		case xml.EndElement:
+			depth--
+			switch {
+			case depth == 0:
+				d.tail = string(data[last:])
+			case depth == 1:
+				switch resource.Type {
+				case TypeString:
+					value.setMarkup(string(data[content:start]))
+					resource.Values = []*Value{value}
+				case TypeStringArray, TypePlurals:
+					resource.suffix = string(data[last:start])
+				default:
+					resource.raw = string(data[begin:end])
+				}
+				resource.endTag = raw
+				d.Resources = append(d.Resources, resource)
+				resource, value, last = nil, nil, end
+			case depth == 2 && value != nil && resource.Type != TypeString:
+				value.setMarkup(string(data[content:start]))
+				resource.Values = append(resource.Values, value)
+				value, last = nil, end
+			}
+		}
+	}
+	if d.head == "" {
+		return nil, errors.New("android: not a resources file")
+	}
+	return d, nil
+}
+
+// Resource returns the resource named name, or nil if there is none.
+func (d *Document) Resource(name string) *Resource {
+	for _, r := range d.Resources {
+		if r.Name == name {
+			return r
+		}
+	}
+	return nil
+}
+
+// Bytes returns d as a resources file.
+func (d *Document) Bytes() []byte {
+	var b bytes.Buffer
+	b.WriteString(d.head)
+	for _, r := range d.Resources {
+		b.WriteString(r.prefix)
+		if r.raw != "" {
+			b.WriteString(r.raw)
+			continue
+		}
+		b.WriteString(r.startTag)
+		if r.Type == TypeString {
+			b.WriteString(r.Values[0].markup)
+		} else {
+			for _, v := range r.Values {
+				b.WriteString(v.prefix + v.startTag + v.markup + "</item>")
+			}
+			b.WriteString(r.suffix)
+		}
+		b.WriteString(r.endTag)
+	}
+	b.WriteString(d.tail)
+	return b.Bytes()
+}
+
+// attr returns the value of the attribute name of e.
+func attr(e xml.StartElement, name string) (string, bool) {
+	for _, a := range e.Attr {
+		if a.Name.Local == name && a.Name.Space == "" {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+// setMarkup sets the content of v, and its text.
+func (v *Value) setMarkup(markup string) {
+	v.markup = markup
+	c := parseContent(markup)
+	v.Text = c.plain(c.text)
+}
+
+// isReference tells whether markup references another resource or a theme
+// attribute instead of holding a text.
+func isReference(markup string) bool {
+	markup = strings.TrimSpace(markup)
+	return strings.HasPrefix(markup, "@") || strings.HasPrefix(markup, "?")
+}
@@ -0,0 +1,87 @@
+package android
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.gilang.dev/translator/v2/internal/placeholder"
+)
+
+const resources = `<?xml version="1.0" encoding="utf-8"?>
+<!-- App strings -->
+<resources xmlns:xliff="urn:oasis:names:tc:xliff:document:1.2">
+    <string name="app_name" translatable="false">Acme</string>
+    <!-- Greeting on the home screen -->
+    <string name="welcome">Welcome, <b>%1$s</b>! Don\'t forget &amp; enjoy.\nBye</string>
+    <string name="quoted">"It's  here"</string>
+    <string name="ref">@string/welcome</string>
+    <string name="count">You have <xliff:g id="count" example="3">%d</xliff:g> new messages</string>
+    <dimen name="margin">16dp</dimen>
+    <plurals name="files">
+        <item quantity="one">%d file</item>
+        <item quantity="other">%d files</item>
+    </plurals>
+    <string-array name="planets">
+        <item>Mercury</item>
+        <item>Venus</item>
+    </string-array>
+</resources>
+`
+
+func TestParse(t *testing.T) {
+	d, err := Parse([]byte(resources))
+	require.NoError(t, err)
+	require.Len(t, d.Resources, 8)
+
+	appName := d.Resource("app_name")
+	require.NotNil(t, appName)
+	assert.Equal(t, TypeString, appName.Type)
+	assert.False(t, appName.Translatable)
+
+	assert.Equal(t, "Welcome, %1$s! Don't forget & enjoy.\nBye", d.Resource("welcome").Values[0].Text)
+	assert.Equal(t, "It's  here", d.Resource("quoted").Values[0].Text)
+	assert.Equal(t, "You have %d new messages", d.Resource("count").Values[0].Text)
+
+	margin := d.Resource("margin")
+	assert.Equal(t, "dimen", margin.Type)
+	assert.False(t, margin.Translatable)
+	assert.Empty(t, margin.Values)
+
+	files := d.Resource("files")
+	assert.Equal(t, TypePlurals, files.Type)
+	require.Len(t, files.Values, 2)
+	assert.Equal(t, "one", files.Values[0].Quantity)
+	assert.Equal(t, "%d files", files.Values[1].Text)
+
+	planets := d.Resource("planets")
+	require.Len(t, planets.Values, 2)
+	assert.Equal(t, "Venus", planets.Values[1].Text)
+
+	assert.Nil(t, d.Resource("missing"))
+	assert.Equal(t, resources, string(d.Bytes()))
+}
+
+func TestParseContent(t *testing.T) {
+	c := parseContent(`Hi <b>%1$s</b>\n\u00e9 \"x\" <xliff:g id="n">%d <i>pts</i></xliff:g>`)
+	assert.Equal(t, "Hi ⟦0⟧⟦1⟧⟦2⟧⟦3⟧⟦4⟧ \"x\" ⟦5⟧", c.text)
+	assert.Equal(t, []string{"<b>", "%1$s", "</b>", `\n`, `\u00e9`, `<xliff:g id="n">%d <i>pts</i></xliff:g>`}, c.tokens)
+	assert.Equal(t, "Hi %1$s\né \"x\" %d pts", c.plain(c.text))
+	assert.Equal(t, []placeholder.Pair{{Open: 0, Close: 2}}, c.pairs)
+	assert.True(t, c.intact("Salut ⟦0⟧⟦1⟧⟦2⟧⟦3⟧⟦4⟧ ⟦5⟧"))
+	assert.False(t, c.intact("Salut ⟦2⟧⟦1⟧⟦0⟧⟦3⟧⟦4⟧ ⟦5⟧"))
+
+	assert.Equal(t, []placeholder.Pair{{Open: 0, Close: 1}}, parseContent(`<![CDATA[Go <a href="x">here</a>]]>`).pairs)
+	assert.False(t, c.quoted)
+
+	assert.Equal(t, `Salut <b>%1$s</b>\n\u00e9 \"x\" l\'ami &amp; <xliff:g id="n">%d <i>pts</i></xliff:g>`,
+		c.markup("Salut ⟦0⟧⟦1⟧⟦2⟧⟦3⟧⟦4⟧ \"x\" l'ami & ⟦5⟧"))
+	assert.Equal(t, `\@home`, parseContent("x").markup("@home"))
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, data := range []string{``, `<html></html>`, `<resources><string>`} {
+		_, err := Parse([]byte(data))
+		assert.Error(t, err, data)
+	}
+}
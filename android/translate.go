@@ -0,0 +1,165 @@
+package android
+
+import (
+	"context"
+	"strings"
+
+	gt "gopkg.gilang.dev/translator/v2"
+	"gopkg.gilang.dev/translator/v2/internal/placeholder"
+	"gopkg.gilang.dev/translator/v2/internal/plural"
+)
+
+// Translator translates string resources with a gt.Translator.
+type Translator struct {
+	translator gt.Translator
+}
+
+// New creates a Translator of string resources using translator.
+func New(translator gt.Translator) *Translator {
+	return &Translator{translator: translator}
+}
+
+// Translate turns d, the resources of the from language, into resources of
+// the to language, to be saved in values-<to>/strings.xml. Strings, string
+// arrays and plurals are translated in a single batch; resources marked
+// translatable="false", references to other resources and other resource
+// types are removed, since they don't belong in a translation. Format
+// specifiers such as %1$s, escape sequences, HTML tags and <xliff:g>
+// elements are left as is, and a value whose tags the backend lost or
+// reordered keeps its source. Plurals get the plural categories of to, each
+// taking the translation of the same category, or of "other".
+func (t *Translator) Translate(ctx context.Context, d *Document, from, to string) error {
+	type job struct {
+		value   *Value
+		content *content
+	}
+	var (
+		resources []*Resource
+		jobs      []job
+		texts     []string
+		indexes   = make(map[string]int)
+	)
+	for _, r := range d.Resources {
+		if !r.Translatable || len(r.Values) == 0 {
+			continue
+		}
+		keep := false
+		for _, v := range r.Values {
+			if isReference(v.markup) {
+				continue
+			}
+			keep = true
+			c := parseContent(v.markup)
+			if placeholder.Empty(c.text) {
+				continue
+			}
+			jobs = append(jobs, job{v, c})
+			if _, ok := indexes[c.text]; !ok {
+				indexes[c.text] = len(texts)
+				texts = append(texts, c.text)
+			}
+		}
+		if keep {
+			resources = append(resources, r)
+		}
+	}
+
+	if len(texts) > 0 {
+		results, err := gt.TranslateBatchWith(ctx, t.translator, texts, from, to)
+		if err != nil {
+			return err
+		}
+		for _, r := range results {
+			if r.Err != nil {
+				return r.Err
+			}
+		}
+		for _, j := range jobs {
+			translation := results[indexes[j.content.text]].Translated.Text
+			if !j.content.intact(translation) {
+				continue // Keep the source rather than broken markup
+			}
+			j.value.markup = j.content.markup(translation)
+			j.value.Text = j.content.plain(translation)
+		}
+	}
+
+	categories := plural.Categories(to)
+	for _, r := range resources {
+		if r.Type == TypePlurals {
+			adaptPlurals(r, categories)
+		}
+	}
+	for _, r := range d.Resources {
+		if !contains(resources, r) {
+			d.dropped(r)
+		}
+	}
+	d.Resources = resources
+	return nil
+}
+
+// adaptPlurals gives r the items of categories, each the item of the same
+// category or of "other".
+func adaptPlurals(r *Resource, categories []string) {
+	if categories == nil || len(r.Values) == 0 {
+		return
+	}
+	items := make(map[string]*Value, len(r.Values))
+	for _, v := range r.Values {
+		items[v.Quantity] = v
+	}
+	fallback := items[plural.Other]
+	if fallback == nil {
+		fallback = r.Values[len(r.Values)-1]
+	}
+
+	values := make([]*Value, len(categories))
+	for i, category := range categories {
+		v, ok := items[category]
+		if !ok {
+			v = &Value{
+				Quantity: category,
+				Text:     fallback.Text,
+				prefix:   r.Values[0].prefix,
+				startTag: `<item quantity="` + category + `">`,
+				markup:   fallback.markup,
+			}
+		}
+		values[i] = v
+	}
+	r.Values = values
+}
+
+func contains(resources []*Resource, r *Resource) bool {
+	for _, resource := range resources {
+		if resource == r {
+			return true
+		}
+	}
+	return false
+}
+
+// dropped keeps the comments before the removed resource r, giving them to
+// the next resource or to the end of the file.
+func (d *Document) dropped(r *Resource) {
+	comments := r.prefix
+	if i := strings.LastIndexByte(comments, '\n'); i >= 0 {
+		comments = comments[:i]
+	} else {
+		comments = ""
+	}
+	if strings.TrimSpace(comments) == "" {
+		return
+	}
+	for i, resource := range d.Resources {
+		if resource == r {
+			if i+1 < len(d.Resources) {
+				d.Resources[i+1].prefix = comments + d.Resources[i+1].prefix
+			} else {
+				d.tail = comments + d.tail
+			}
+			return
+		}
+	}
+}
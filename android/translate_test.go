@@ -0,0 +1,84 @@
+package android
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.gilang.dev/translator/v2/internal/translatortest"
+)
+
+func TestTranslate(t *testing.T) {
+	d, err := Parse([]byte(resources))
+	require.NoError(t, err)
+	u := &translatortest.Upper{}
+	require.NoError(t, New(u).Translate(context.Background(), d, "en", "ru"))
+
+	want := `<?xml version="1.0" encoding="utf-8"?>
+<!-- App strings -->
+<resources xmlns:xliff="urn:oasis:names:tc:xliff:document:1.2">
+    <!-- Greeting on the home screen -->
+    <string name="welcome">WELCOME, <b>%1$s</b>! DON\'T FORGET &amp; ENJOY.\nBYE</string>
+    <string name="quoted">"IT'S  HERE"</string>
+    <string name="count">YOU HAVE <xliff:g id="count" example="3">%d</xliff:g> NEW MESSAGES</string>
+    <plurals name="files">
+        <item quantity="one">%d FILE</item>
+        <item quantity="few">%d FILES</item>
+        <item quantity="many">%d FILES</item>
+        <item quantity="other">%d FILES</item>
+    </plurals>
+    <string-array name="planets">
+        <item>MERCURY</item>
+        <item>VENUS</item>
+    </string-array>
+</resources>
+`
+	assert.Equal(t, want, string(d.Bytes()))
+	assert.Len(t, u.Texts(), 7)
+	assert.Contains(t, u.Texts(), "Welcome, ⟦0⟧⟦1⟧⟦2⟧! Don't forget & enjoy.⟦3⟧Bye")
+	assert.Equal(t, "%d FILES", d.Resource("files").Values[1].Text)
+}
+
+func TestTranslateSingleForm(t *testing.T) {
+	d, err := Parse([]byte(`<resources>
+  <plurals name="days"><item quantity="one">%d day</item><item quantity="other">%d days</item></plurals>
+  <string name="unused" translatable="false">x</string>
+</resources>`))
+	require.NoError(t, err)
+	require.NoError(t, New(&translatortest.Upper{}).Translate(context.Background(), d, "en", "ja"))
+	assert.Equal(t, `<resources>
+  <plurals name="days"><item quantity="other">%d DAYS</item></plurals>
+</resources>`, string(d.Bytes()))
+}
+
+func TestTranslateError(t *testing.T) {
+	d, err := Parse([]byte(resources))
+	require.NoError(t, err)
+	u := &translatortest.Upper{Err: errors.New("backend down")}
+	assert.ErrorIs(t, New(u).Translate(context.Background(), d, "en", "id"), u.Err)
+	assert.Len(t, d.Resources, 8)
+}
+
+func TestTranslateBrokenMarkup(t *testing.T) {
+	for name, rewrite := range map[string]func(string) string{
+		"dropped": func(text string) string { return strings.Replace(text, "⟦2⟧", "", 1) },
+		"swapped": strings.NewReplacer("⟦0⟧", "⟦2⟧", "⟦2⟧", "⟦0⟧").Replace,
+	} {
+		t.Run(name, func(t *testing.T) {
+			d, err := Parse([]byte(`<resources>
+  <string name="link">Open <b>%1$s</b> now</string>
+  <string name="plain">Close</string>
+</resources>`))
+			require.NoError(t, err)
+			require.NoError(t, New(&translatortest.Upper{Rewrite: rewrite}).Translate(context.Background(), d, "en", "id"))
+			assert.Equal(t, `<resources>
+  <string name="link">Open <b>%1$s</b> now</string>
+  <string name="plain">CLOSE</string>
+</resources>`, string(d.Bytes()))
+			assert.Equal(t, "Open %1$s now", d.Resource("link").Values[0].Text)
+		})
+	}
+}
@@ -0,0 +1,269 @@
+// Package apple reads, writes and translates Apple string resources:
+// .strings files and .stringsdict plural rules.
+package apple
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	xunicode "golang.org/x/text/encoding/unicode"
+)
+
+// Strings is a .strings file.
+type Strings struct {
+	Entries []*Entry
+
+	text     string // File decoded to UTF-8
+	encoding encoding
+}
+
+// Entry is a "key" = "value"; pair of a .strings file.
+type Entry struct {
+	Comment string // Comment before the entry, without its delimiters
+	Key     string
+	Value   string
+
+	value   span // Quoted value, or where to insert it for "key"; entries
+	changed bool
+}
+
+const bom = "\uFEFF"
+
+// span is a byte range of a file.
+type span struct{ start, end int }
+
+// encoding is the encoding of a .strings file.
+type encoding int
+
+const (
+	utf8Encoding encoding = iota
+	utf8BOMEncoding
+	utf16LEEncoding
+	utf16BEEncoding
+)
+
+// SetValue sets the value of e.
+func (e *Entry) SetValue(value string) {
+	e.Value = value
+	e.changed = true
+}
+
+// ParseStrings parses a .strings file, encoded in UTF-8 or, with a byte
+// order mark, UTF-16.
+func ParseStrings(data []byte) (*Strings, error) {
+	f := &Strings{}
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}), bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		f.encoding = utf16LEEncoding
+		if data[0] == 0xFE {
+			f.encoding = utf16BEEncoding
+		}
+		decoded, err := xunicode.UTF16(xunicode.LittleEndian, xunicode.ExpectBOM).NewDecoder().Bytes(data)
+		if err != nil {
+			return nil, fmt.Errorf("apple: %w", err)
+		}
+		data = decoded
+	case bytes.HasPrefix(data, []byte(bom)):
+		f.encoding = utf8BOMEncoding
+		data = data[len(bom):]
+	}
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("apple: invalid UTF-8")
+	}
+	f.text = string(data)
+
+	l := &lexer{text: f.text}
+	for {
+		comment, err := l.skip()
+		if err != nil {
+			return nil, err
+		}
+		if l.pos == len(l.text) {
+			return f, nil
+		}
+		key, _, err := l.string()
+		if err != nil {
+			return nil, err
+		}
+		entry := &Entry{Comment: comment, Key: key}
+
+		if _, err := l.skip(); err != nil {
+			return nil, err
+		}
+		switch l.peek() {
+		case ';':
+			// Legacy "key"; entries use the key as value
+			entry.Value, entry.value = key, span{l.pos, l.pos}
+		case '=':
+			l.pos++
+			if _, err := l.skip(); err != nil {
+				return nil, err
+			}
+			start := l.pos
+			if entry.Value, _, err = l.string(); err != nil {
+				return nil, err
+			}
+			entry.value = span{start, l.pos}
+			if _, err := l.skip(); err != nil {
+				return nil, err
+			}
+		}
+		if l.peek() != ';' {
+			return nil, l.errorf("expected ;")
+		}
+		l.pos++
+		f.Entries = append(f.Entries, entry)
+	}
+}
+
+// Entry returns the entry of key, or nil if there is none.
+func (f *Strings) Entry(key string) *Entry {
+	for _, e := range f.Entries {
+		if e.Key == key {
+			return e
+		}
+	}
+	return nil
+}
+
+// Bytes returns f in the encoding of the parsed file.
+func (f *Strings) Bytes() []byte {
+	var (
+		b    strings.Builder
+		last int
+	)
+	for _, e := range f.Entries {
+		if !e.changed {
+			continue
+		}
+		b.WriteString(f.text[last:e.value.start])
+		if e.value.start == e.value.end {
+			b.WriteString(" = ")
+		}
+		b.WriteString(quote(e.Value))
+		last = e.value.end
+	}
+	b.WriteString(f.text[last:])
+
+	switch f.encoding {
+	case utf8BOMEncoding:
+		return []byte(bom + b.String())
+	case utf16LEEncoding, utf16BEEncoding:
+		endianness := xunicode.LittleEndian
+		if f.encoding == utf16BEEncoding {
+			endianness = xunicode.BigEndian
+		}
+		data, _ := xunicode.UTF16(endianness, xunicode.UseBOM).NewEncoder().Bytes([]byte(b.String()))
+		return data
+	}
+	return []byte(b.String())
+}
+
+// lexer reads the tokens of a .strings file.
+type lexer struct {
+	text string
+	pos  int
+}
+
+func (l *lexer) peek() byte {
+	if l.pos < len(l.text) {
+		return l.text[l.pos]
+	}
+	return 0
+}
+
+func (l *lexer) errorf(format string, args ...any) error {
+	line := strings.Count(l.text[:l.pos], "\n") + 1
+	return fmt.Errorf("apple: line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+// skip skips whitespace and comments, and returns the text of the last
+// comment.
+func (l *lexer) skip() (string, error) {
+	comment := ""
+	for l.pos < len(l.text) {
+		rest := l.text[l.pos:]
+		switch {
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest[2:], "*/")
+			if end < 0 {
+				return "", l.errorf("unterminated comment")
+			}
+			comment = strings.TrimSpace(rest[2 : 2+end])
+			l.pos += end + 4
+		case strings.HasPrefix(rest, "//"):
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			comment = strings.TrimSpace(rest[2:end])
+			l.pos += end
+		default:
+			r, size := utf8.DecodeRuneInString(rest)
+			if !unicode.IsSpace(r) {
+				return comment, nil
+			}
+			l.pos += size
+		}
+	}
+	return comment, nil
+}
+
+// string reads a quoted or unquoted string, and tells whether it was quoted.
+func (l *lexer) string() (string, bool, error) {
+	if l.peek() != '"' {
+		start := l.pos
+		for l.pos < len(l.text) && !strings.ContainsRune(" \t\r\n=;\"/", rune(l.text[l.pos])) {
+			l.pos++
+		}
+		if l.pos == start {
+			return "", false, l.errorf("expected a string")
+		}
+		return l.text[start:l.pos], false, nil
+	}
+
+	var b strings.Builder
+	for i := l.pos + 1; i < len(l.text); i++ {
+		switch c := l.text[i]; c {
+		case '"':
+			l.pos = i + 1
+			return b.String(), true, nil
+		case '\\':
+			if i+1 == len(l.text) {
+				break
+			}
+			i++
+			switch e := l.text[i]; e {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case '0':
+				b.WriteByte(0)
+			case 'U', 'u':
+				if n, err := strconv.ParseUint(l.text[i+1:min(i+5, len(l.text))], 16, 32); err == nil && i+5 <= len(l.text) {
+					b.WriteRune(rune(n))
+					i += 4
+					continue
+				}
+				b.WriteByte(e)
+			default:
+				b.WriteByte(e)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", false, l.errorf("unterminated string")
+}
+
+// quote quotes s as a .strings string.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`).Replace(s) + `"`
+}
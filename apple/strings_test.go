@@ -0,0 +1,66 @@
+package apple
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	xunicode "golang.org/x/text/encoding/unicode"
+)
+
+const stringsFile = `/* Title of the main window */
+"title" = "Welcome, %@!";
+
+// Legacy entry
+"OK";
+greeting = "Say \"hi\"\n\U00e9";
+"count" = "%1$d of %2$lld";
+`
+
+func TestParseStrings(t *testing.T) {
+	f, err := ParseStrings([]byte(stringsFile))
+	require.NoError(t, err)
+	require.Len(t, f.Entries, 4)
+
+	assert.Equal(t, "Title of the main window", f.Entries[0].Comment)
+	assert.Equal(t, "title", f.Entries[0].Key)
+	assert.Equal(t, "Welcome, %@!", f.Entries[0].Value)
+	assert.Equal(t, "Legacy entry", f.Entries[1].Comment)
+	assert.Equal(t, "OK", f.Entries[1].Value)
+	assert.Equal(t, "Say \"hi\"\né", f.Entry("greeting").Value)
+	assert.Nil(t, f.Entry("missing"))
+	assert.Equal(t, stringsFile, string(f.Bytes()))
+
+	f.Entry("title").SetValue(`Bienvenue, %@ "!"`)
+	f.Entry("OK").SetValue("D'accord")
+	assert.Equal(t, `/* Title of the main window */
+"title" = "Bienvenue, %@ \"!\"";
+
+// Legacy entry
+"OK" = "D'accord";
+greeting = "Say \"hi\"\n\U00e9";
+"count" = "%1$d of %2$lld";
+`, string(f.Bytes()))
+}
+
+func TestParseStringsUTF16(t *testing.T) {
+	for _, endianness := range []xunicode.Endianness{xunicode.LittleEndian, xunicode.BigEndian} {
+		data, err := xunicode.UTF16(endianness, xunicode.UseBOM).NewEncoder().Bytes([]byte(`"a" = "b";`))
+		require.NoError(t, err)
+		f, err := ParseStrings(data)
+		require.NoError(t, err)
+		assert.Equal(t, "b", f.Entries[0].Value)
+		assert.Equal(t, data, f.Bytes())
+	}
+
+	f, err := ParseStrings([]byte("\uFEFF\"a\" = \"b\";"))
+	require.NoError(t, err)
+	assert.Equal(t, "\uFEFF\"a\" = \"b\";", string(f.Bytes()))
+}
+
+func TestParseStringsErrors(t *testing.T) {
+	for _, data := range []string{`"a" = "b"`, `"a" = "b`, `/* open`, `"a" "b";`, `= "b";`, "\xff"} {
+		_, err := ParseStrings([]byte(data))
+		assert.Error(t, err, data)
+	}
+}
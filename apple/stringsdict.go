@@ -0,0 +1,297 @@
+package apple
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.gilang.dev/translator/v2/internal/plural"
+)
+
+// Keys of .stringsdict files.
+const (
+	formatKey   = "NSStringLocalizedFormatKey"
+	specTypeKey = "NSStringFormatSpecTypeKey"
+	pluralRule  = "NSStringPluralRuleType"
+)
+
+// Stringsdict is a .stringsdict file.
+type Stringsdict struct {
+	Entries []*StringsdictEntry
+
+	data []byte
+}
+
+// StringsdictEntry is a localized format with its plural variables.
+type StringsdictEntry struct {
+	Key       string
+	Format    string // NSStringLocalizedFormatKey, such as "%#@files@"
+	Variables []*Variable
+
+	format  span // Content of the format string
+	changed bool
+}
+
+// Variable is a plural variable of a localized format.
+type Variable struct {
+	Name       string
+	Categories []string          // Plural categories, in the order of the file
+	Forms      map[string]string // Form of each plural category
+
+	inner   span   // Content of the dict
+	pairs   []pair // Key/value pairs of the dict
+	changed bool
+}
+
+// pair is a key/value pair of a dict.
+type pair struct {
+	key      string
+	start    int    // Start of the key
+	keyEnd   int    // End of the key
+	valueAt  int    // Start of the value
+	end      int    // End of the value
+	content  span   // Content of a string value
+	value    string // Text of a string value
+	isString bool
+}
+
+// dictFrame is a dict being read.
+type dictFrame struct {
+	key       string // Key of the dict in its parent
+	inner     int    // Start of its content
+	pairs     []pair
+	variables []*Variable
+	keyAt     int    // Start of the pending key
+	keyEnd    int    // End of the pending key
+	pending   string // Key waiting for its value
+	hasKey    bool
+}
+
+// ParseStringsdict parses a .stringsdict file.
+func ParseStringsdict(data []byte) (*Stringsdict, error) {
+	d := &Stringsdict{data: data}
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var (
+		frames  []*dictFrame
+		text    strings.Builder
+		content int // Start of the content of the key or value being read
+		valueAt int // Start of the value being read
+		depth   int // Depth of the value being read in its dict
+		root    bool
+	)
+	for {
+		start := int(dec.InputOffset())
+		token, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("apple: %w", err)
+		}
+		end := int(dec.InputOffset())
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "plist" {
+				root = true
+			}
+			if len(frames) == 0 && t.Name.Local != "dict" {
+				continue
+			}
+			if len(frames) > 0 && depth > 0 {
+				depth++
+				continue
+			}
+			switch t.Name.Local {
+			case "dict":
+				frame := &dictFrame{inner: end}
+				if len(frames) > 0 {
+					parent := frames[len(frames)-1]
+					frame.key = parent.pending
+				}
+				frames = append(frames, frame)
+			case "key":
+				frames[len(frames)-1].keyAt = start
+				text.Reset()
+				depth = 1
+			default:
+				valueAt, content = start, end
+				text.Reset()
+				depth = 1
+			}
+		case xml.CharData:
+			if depth == 1 {
+				text.Write(t)
+			}
+		case xml.EndElement:
+			if len(frames) == 0 {
+				continue
+			}
+			frame := frames[len(frames)-1]
+			if depth > 1 {
+				depth--
+				continue
+			}
+			if depth == 1 {
+				depth = 0
+				if t.Name.Local == "key" {
+					frame.pending, frame.keyEnd, frame.hasKey = text.String(), end, true
+					continue
+				}
+				if frame.hasKey {
+					frame.pairs = append(frame.pairs, pair{
+						key:      frame.pending,
+						start:    frame.keyAt,
+						keyEnd:   frame.keyEnd,
+						valueAt:  valueAt,
+						end:      end,
+						content:  span{content, start},
+						value:    text.String(),
+						isString: t.Name.Local == "string",
+					})
+					frame.hasKey = false
+				}
+				continue
+			}
+
+			// End of a dict
+			frames = frames[:len(frames)-1]
+			var parent *dictFrame
+			if len(frames) > 0 {
+				parent = frames[len(frames)-1]
+				if parent.hasKey {
+					parent.pairs = append(parent.pairs, pair{key: parent.pending, start: parent.keyAt, keyEnd: parent.keyEnd, valueAt: frame.inner, end: end})
+					parent.hasKey = false
+				}
+			}
+			d.endDict(frame, parent, start)
+		}
+	}
+	if !root {
+		return nil, errors.New("apple: not a property list")
+	}
+	return d, nil
+}
+
+// endDict records frame, a dict ending at end, as a plural variable of its
+// parent or as an entry.
+func (d *Stringsdict) endDict(frame, parent *dictFrame, end int) {
+	values := make(map[string]pair, len(frame.pairs))
+	for _, p := range frame.pairs {
+		values[p.key] = p
+	}
+
+	if values[specTypeKey].value == pluralRule && parent != nil {
+		v := &Variable{Name: frame.key, Forms: make(map[string]string), inner: span{frame.inner, end}, pairs: frame.pairs}
+		for _, p := range frame.pairs {
+			if isCategory(p.key) && p.isString {
+				v.Categories = append(v.Categories, p.key)
+				v.Forms[p.key] = p.value
+			}
+		}
+		parent.variables = append(parent.variables, v)
+		return
+	}
+	if format, ok := values[formatKey]; ok && format.isString {
+		d.Entries = append(d.Entries, &StringsdictEntry{
+			Key:       frame.key,
+			Format:    format.value,
+			Variables: frame.variables,
+			format:    format.content,
+		})
+	}
+}
+
+// Entry returns the entry of key, or nil if there is none.
+func (d *Stringsdict) Entry(key string) *StringsdictEntry {
+	for _, e := range d.Entries {
+		if e.Key == key {
+			return e
+		}
+	}
+	return nil
+}
+
+// SetFormat sets the localized format of e.
+func (e *StringsdictEntry) SetFormat(format string) {
+	e.Format = format
+	e.changed = true
+}
+
+// SetForms sets the plural forms of v, in the order of categories.
+func (v *Variable) SetForms(categories []string, forms map[string]string) {
+	v.Categories = categories
+	v.Forms = forms
+	v.changed = true
+}
+
+// Bytes returns d with the formats and forms set since it was parsed.
+func (d *Stringsdict) Bytes() []byte {
+	type edit struct {
+		span
+		text string
+	}
+	var edits []edit
+	for _, e := range d.Entries {
+		if e.changed {
+			edits = append(edits, edit{e.format, escapeXML(e.Format)})
+		}
+		for _, v := range e.Variables {
+			if v.changed {
+				edits = append(edits, edit{v.inner, d.variableMarkup(v)})
+			}
+		}
+	}
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+
+	var (
+		b    bytes.Buffer
+		last int
+	)
+	for _, e := range edits {
+		b.Write(d.data[last:e.start])
+		b.WriteString(e.text)
+		last = e.end
+	}
+	b.Write(d.data[last:])
+	return b.Bytes()
+}
+
+// variableMarkup returns the content of the dict of v: its pairs other than
+// plural forms as they are, then its forms, indented like the first pair.
+func (d *Stringsdict) variableMarkup(v *Variable) string {
+	if len(v.pairs) == 0 {
+		return string(d.data[v.inner.start:v.inner.end])
+	}
+	first, last := v.pairs[0], v.pairs[len(v.pairs)-1]
+	indent := string(d.data[v.inner.start:first.start])
+	separator := string(d.data[first.keyEnd:first.valueAt])
+
+	var b strings.Builder
+	for _, p := range v.pairs {
+		if !isCategory(p.key) {
+			b.WriteString(indent + string(d.data[p.start:p.end]))
+		}
+	}
+	for _, category := range v.Categories {
+		b.WriteString(indent + "<key>" + category + "</key>" + separator + "<string>" + escapeXML(v.Forms[category]) + "</string>")
+	}
+	b.WriteString(string(d.data[last.end:v.inner.end]))
+	return b.String()
+}
+
+func isCategory(key string) bool {
+	switch key {
+	case plural.Zero, plural.One, plural.Two, plural.Few, plural.Many, plural.Other:
+		return true
+	}
+	return false
+}
+
+func escapeXML(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,73 @@
+package apple
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const stringsdictFile = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>files_count</key>
+	<dict>
+		<key>NSStringLocalizedFormatKey</key>
+		<string>You have %#@files@ &amp; more</string>
+		<key>files</key>
+		<dict>
+			<key>NSStringFormatSpecTypeKey</key>
+			<string>NSStringPluralRuleType</string>
+			<key>NSStringFormatValueTypeKey</key>
+			<string>d</string>
+			<key>one</key>
+			<string>%d file</string>
+			<key>other</key>
+			<string>%d files</string>
+		</dict>
+	</dict>
+</dict>
+</plist>
+`
+
+func TestParseStringsdict(t *testing.T) {
+	d, err := ParseStringsdict([]byte(stringsdictFile))
+	require.NoError(t, err)
+	require.Len(t, d.Entries, 1)
+
+	e := d.Entry("files_count")
+	require.NotNil(t, e)
+	assert.Equal(t, "You have %#@files@ & more", e.Format)
+	require.Len(t, e.Variables, 1)
+	v := e.Variables[0]
+	assert.Equal(t, "files", v.Name)
+	assert.Equal(t, []string{"one", "other"}, v.Categories)
+	assert.Equal(t, map[string]string{"one": "%d file", "other": "%d files"}, v.Forms)
+	assert.Nil(t, d.Entry("missing"))
+	assert.Equal(t, stringsdictFile, string(d.Bytes()))
+
+	e.SetFormat("Vous avez %#@files@ & plus")
+	v.SetForms([]string{"one", "many", "other"}, map[string]string{"one": "%d fichier", "many": "%d de fichiers", "other": "%d fichiers"})
+	want := strings.NewReplacer(
+		"You have %#@files@ &amp; more", "Vous avez %#@files@ &amp; plus",
+		`			<key>one</key>
+			<string>%d file</string>
+			<key>other</key>
+			<string>%d files</string>`, `			<key>one</key>
+			<string>%d fichier</string>
+			<key>many</key>
+			<string>%d de fichiers</string>
+			<key>other</key>
+			<string>%d fichiers</string>`,
+	).Replace(stringsdictFile)
+	assert.Equal(t, want, string(d.Bytes()))
+}
+
+func TestParseStringsdictErrors(t *testing.T) {
+	for _, data := range []string{``, `<html></html>`, `<plist><dict>`} {
+		_, err := ParseStringsdict([]byte(data))
+		assert.Error(t, err, data)
+	}
+}
@@ -0,0 +1,129 @@
+package apple
+
+import (
+	"context"
+	"regexp"
+
+	gt "gopkg.gilang.dev/translator/v2"
+	"gopkg.gilang.dev/translator/v2/internal/placeholder"
+	"gopkg.gilang.dev/translator/v2/internal/plural"
+)
+
+// protected matches the parts of texts that are never translated: stringsdict
+// variables such as %#@files@, format specifiers such as %@, %1$@ or %lld,
+// and line breaks.
+var protected = []*regexp.Regexp{
+	regexp.MustCompile(`%(?:\d+\$)?#@[^@\s]+@`),
+	placeholder.Printf,
+	regexp.MustCompile(`\r?\n`),
+}
+
+// Translator translates .strings and .stringsdict files with a
+// gt.Translator.
+type Translator struct {
+	translator gt.Translator
+}
+
+// New creates a Translator of Apple string resources using translator.
+func New(translator gt.Translator) *Translator {
+	return &Translator{translator: translator}
+}
+
+// TranslateStrings translates the values of f in a single batch, keeping
+// its keys, comments and encoding. Format specifiers such as %@ or %1$@ are
+// left as is.
+func (t *Translator) TranslateStrings(ctx context.Context, f *Strings, from, to string) error {
+	b := newBatch()
+	for _, e := range f.Entries {
+		b.add(e.Value, e.SetValue)
+	}
+	return b.translate(ctx, t.translator, from, to)
+}
+
+// TranslateStringsdict translates the localized formats and plural forms of
+// d in a single batch. Plural variables get the plural categories of to,
+// each taking the translation of the same category, or of "other".
+func (t *Translator) TranslateStringsdict(ctx context.Context, d *Stringsdict, from, to string) error {
+	b := newBatch()
+	for _, e := range d.Entries {
+		b.add(e.Format, e.SetFormat)
+		for _, v := range e.Variables {
+			forms := make(map[string]string, len(v.Forms))
+			for category, form := range v.Forms {
+				forms[category] = form
+			}
+			for _, category := range v.Categories {
+				b.add(forms[category], func(s string) { forms[category] = s })
+			}
+			b.done(func() {
+				categories := plural.Categories(to)
+				if categories == nil {
+					categories = v.Categories
+				}
+				v.SetForms(categories, plural.Adapt(forms, categories))
+			})
+		}
+	}
+	return b.translate(ctx, t.translator, from, to)
+}
+
+// batch collects texts to translate in a single batch.
+type batch struct {
+	texts   []string
+	tokens  [][]string
+	sets    [][]func(string) // Setters of the translation of each text
+	indexes map[string]int
+	dones   []func()
+}
+
+func newBatch() *batch {
+	return &batch{indexes: make(map[string]int)}
+}
+
+// add adds text, whose translation is given to set, unless it has nothing
+// to translate.
+func (b *batch) add(text string, set func(string)) {
+	protectedText, tokens := placeholder.Protect(text, protected...)
+	if placeholder.Empty(protectedText) {
+		return
+	}
+	i, ok := b.indexes[text]
+	if !ok {
+		i = len(b.texts)
+		b.indexes[text] = i
+		b.texts = append(b.texts, protectedText)
+		b.tokens = append(b.tokens, tokens)
+		b.sets = append(b.sets, nil)
+	}
+	b.sets[i] = append(b.sets[i], set)
+}
+
+// done adds a function called once the translations are set.
+func (b *batch) done(f func()) {
+	b.dones = append(b.dones, f)
+}
+
+// translate translates the texts of b and sets their translations.
+func (b *batch) translate(ctx context.Context, translator gt.Translator, from, to string) error {
+	if len(b.texts) > 0 {
+		results, err := gt.TranslateBatchWith(ctx, translator, b.texts, from, to)
+		if err != nil {
+			return err
+		}
+		for _, r := range results {
+			if r.Err != nil {
+				return r.Err
+			}
+		}
+		for i, r := range results {
+			translation := placeholder.Restore(r.Translated.Text, b.tokens[i])
+			for _, set := range b.sets[i] {
+				set(translation)
+			}
+		}
+	}
+	for _, done := range b.dones {
+		done()
+	}
+	return nil
+}
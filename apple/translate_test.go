@@ -0,0 +1,72 @@
+package apple
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.gilang.dev/translator/v2/internal/translatortest"
+)
+
+func TestTranslateStrings(t *testing.T) {
+	f, err := ParseStrings([]byte(stringsFile))
+	require.NoError(t, err)
+	u := &translatortest.Upper{}
+	require.NoError(t, New(u).TranslateStrings(context.Background(), f, "en", "fr"))
+
+	assert.Equal(t, `/* Title of the main window */
+"title" = "WELCOME, %@!";
+
+// Legacy entry
+"OK" = "OK";
+greeting = "SAY \"HI\"\nÉ";
+"count" = "%1$d OF %2$lld";
+`, string(f.Bytes()))
+	assert.ElementsMatch(t, []string{"Welcome, ⟦0⟧!", "OK", "Say \"hi\"⟦0⟧é", "⟦0⟧ of ⟦1⟧"}, u.Texts())
+}
+
+func TestTranslateStringsdict(t *testing.T) {
+	d, err := ParseStringsdict([]byte(stringsdictFile))
+	require.NoError(t, err)
+	u := &translatortest.Upper{}
+	require.NoError(t, New(u).TranslateStringsdict(context.Background(), d, "en", "ru"))
+
+	want := strings.NewReplacer(
+		"You have %#@files@ &amp; more", "YOU HAVE %#@files@ &amp; MORE",
+		`			<key>one</key>
+			<string>%d file</string>
+			<key>other</key>
+			<string>%d files</string>`, `			<key>one</key>
+			<string>%d FILE</string>
+			<key>few</key>
+			<string>%d FILES</string>
+			<key>many</key>
+			<string>%d FILES</string>
+			<key>other</key>
+			<string>%d FILES</string>`,
+	).Replace(stringsdictFile)
+	assert.Equal(t, want, string(d.Bytes()))
+	assert.ElementsMatch(t, []string{"You have ⟦0⟧ & more", "⟦0⟧ file", "⟦0⟧ files"}, u.Texts())
+
+	// Unknown languages keep the categories of the file
+	d, err = ParseStringsdict([]byte(stringsdictFile))
+	require.NoError(t, err)
+	require.NoError(t, New(u).TranslateStringsdict(context.Background(), d, "en", "haw"))
+	assert.Equal(t, []string{"one", "other"}, d.Entries[0].Variables[0].Categories)
+}
+
+func TestTranslateError(t *testing.T) {
+	f, err := ParseStrings([]byte(stringsFile))
+	require.NoError(t, err)
+	u := &translatortest.Upper{Err: errors.New("backend down")}
+	assert.ErrorIs(t, New(u).TranslateStrings(context.Background(), f, "en", "fr"), u.Err)
+	assert.Equal(t, stringsFile, string(f.Bytes()))
+
+	d, err := ParseStringsdict([]byte(stringsdictFile))
+	require.NoError(t, err)
+	assert.ErrorIs(t, New(u).TranslateStringsdict(context.Background(), d, "en", "fr"), u.Err)
+	assert.Equal(t, stringsdictFile, string(d.Bytes()))
+}
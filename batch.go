@@ -0,0 +1,203 @@
+package gt
+
+import (
+	"context"
+	"sync"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+	"gopkg.gilang.dev/translator/v2/deepl"
+	"gopkg.gilang.dev/translator/v2/ratelimit"
+)
+
+// DefaultBatchConcurrency is the number of parallel requests used when a
+// translator has no native batch support.
+const DefaultBatchConcurrency = 4
+
+// BatchTranslator is implemented by translators that can translate several
+// texts at once.
+type BatchTranslator interface {
+	TranslateBatch(ctx context.Context, texts []string, from, to string) ([]BatchResult, error)
+}
+
+// requestBatcher is implemented by BatchTranslators sending a batch in a few
+// requests, unlike those translating its texts one by one.
+// translateBatchRequests waits for limiter before each request it sends.
+type requestBatcher interface {
+	BatchTranslator
+	translateBatchRequests(ctx context.Context, texts []string, from, to string, limiter *ratelimit.Limiter) ([]BatchResult, error)
+}
+
+// BatchResult represents the outcome for a single text of a batch translation.
+type BatchResult struct {
+	Translated *Translated `json:"translated,omitempty"`
+	Err        error       `json:"-"`
+}
+
+// TranslateBatch implements BatchTranslator with bounded-parallel calls.
+func (g *googleTranslateAdapter) TranslateBatch(ctx context.Context, texts []string, from, to string) ([]BatchResult, error) {
+	return translateParallel(ctx, g, texts, from, to, DefaultBatchConcurrency), nil
+}
+
+// TranslateBatch implements BatchTranslator with as few DeepL requests as the
+// client's MaxChars allows, one per detected language when from is "auto".
+// Texts longer than MaxChars are split. A request failing sets the error of
+// its texts only.
+func (d *deeplAdapter) TranslateBatch(ctx context.Context, texts []string, from, to string) ([]BatchResult, error) {
+	return d.translateBatchRequests(ctx, texts, from, to, nil)
+}
+
+func (d *deeplAdapter) translateBatchRequests(ctx context.Context, texts []string, from, to string, limiter *ratelimit.Limiter) ([]BatchResult, error) {
+	languages, groups := []string{from}, [][]int{make([]int, len(texts))}
+	for i := range texts {
+		groups[0][i] = i
+	}
+	if from == "" || from == "auto" {
+		languages, groups = deepl.GroupByLanguage(texts)
+	}
+
+	maxChars := d.client.MaxChars()
+	results := make([]BatchResult, len(texts))
+	for n, indexes := range groups {
+		group := make([]string, len(indexes))
+		for j, i := range indexes {
+			group[j] = texts[i]
+		}
+		for _, request := range groupTexts(group, maxChars) {
+			if len(request) == 1 && maxChars > 0 && utf8.RuneCountInString(group[request[0]]) > maxChars {
+				// Every chunk is a request of its own
+				i := indexes[request[0]]
+				results[i].Translated, results[i].Err = translateChunks(ctx, NewRateLimitTranslator(d, limiter), splitText(texts[i], maxChars), languages[n], to, 1)
+				continue
+			}
+			batch := make([]string, len(request))
+			chars := 0
+			for k, j := range request {
+				batch[k] = group[j]
+				chars += utf8.RuneCountInString(group[j])
+			}
+			err := limiter.Wait(ctx, chars)
+			var batchResults []BatchResult
+			if err == nil {
+				batchResults, err = d.translateBatch(ctx, batch, languages[n], to)
+			}
+			for k, j := range request {
+				i := indexes[j]
+				if err != nil {
+					// Keep the results of the requests already sent
+					results[i].Err = err
+					continue
+				}
+				results[i] = batchResults[k]
+			}
+		}
+	}
+	return results, nil
+}
+
+// groupTexts groups consecutive texts, returning their indexes, so that each
+// group has at most maxChars characters. A longer text is alone in its group.
+func groupTexts(texts []string, maxChars int) [][]int {
+	var (
+		groups [][]int
+		group  []int
+		chars  int
+	)
+	for i, text := range texts {
+		n := utf8.RuneCountInString(text)
+		if len(group) > 0 && maxChars > 0 && chars+n > maxChars {
+			groups = append(groups, group)
+			group, chars = nil, 0
+		}
+		group = append(group, i)
+		chars += n
+	}
+	if len(group) > 0 {
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// translateBatch translates texts with a single DeepL request.
+func (d *deeplAdapter) translateBatch(ctx context.Context, texts []string, from, to string) ([]BatchResult, error) {
+	from, to, err := languagePair(DeepL, from, to)
+	if err != nil {
+		return nil, err
+	}
+	results, err := d.client.TranslateBatchWithOptions(ctx, texts, from, to, deeplOptions(ctx))
+	if err != nil {
+		return nil, err
+	}
+	batch := make([]BatchResult, len(results))
+	for i, result := range results {
+		if result.Err != nil {
+			batch[i].Err = result.Err
+			continue
+		}
+		batch[i].Translated = fromDeepL(result.Translated)
+	}
+	return batch, nil
+}
+
+// translateParallel translates texts one by one using at most limit goroutines.
+func translateParallel(ctx context.Context, translator Translator, texts []string, from, to string, limit int) []BatchResult {
+	if limit < 1 {
+		limit = 1
+	}
+	results := make([]BatchResult, len(texts))
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i, text := range texts {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(i int, text string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i].Translated, results[i].Err = translator.Translate(ctx, text, from, to)
+		}(i, text)
+	}
+	wg.Wait()
+	return results
+}
+
+// batchWith translates texts with translator, using its native batch support when available.
+func batchWith(ctx context.Context, translator Translator, texts []string, from, to string) ([]BatchResult, error) {
+	if batcher, ok := translator.(BatchTranslator); ok {
+		return batcher.TranslateBatch(ctx, texts, from, to)
+	}
+	return translateParallel(ctx, translator, texts, from, to, DefaultBatchConcurrency), nil
+}
+
+// TranslateBatch translates several texts with the default translator.
+// Use "auto" as fromLanguage to detect the source language.
+func TranslateBatch(ctx context.Context, texts []string, fromLanguage, toLanguage string) ([]BatchResult, error) {
+	return TranslateBatchWith(ctx, getTranslator(), texts, fromLanguage, toLanguage)
+}
+
+// TranslateBatchWith translates several texts using a specific translator.
+// Use "auto" as fromLanguage to detect the source language.
+func TranslateBatchWith(ctx context.Context, translator Translator, texts []string, fromLanguage, toLanguage string) ([]BatchResult, error) {
+	if len(texts) == 0 {
+		return nil, errTextRequired()
+	}
+	if fromLanguage == "" {
+		fromLanguage = "auto"
+	}
+	if fromLanguage != "auto" {
+		if _, err := language.Parse(fromLanguage); err != nil {
+			return nil, errLanguageInvalid("From", err)
+		}
+	}
+	if toLanguage == "" {
+		return nil, errLanguageRequired("To")
+	}
+	if _, err := language.Parse(toLanguage); err != nil {
+		return nil, errLanguageInvalid("To", err)
+	}
+	return batchWith(ctx, translator, texts, fromLanguage, toLanguage)
+}
@@ -0,0 +1,93 @@
+package gt
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.gilang.dev/translator/v2/deepl"
+	"gopkg.gilang.dev/translator/v2/params"
+	"gopkg.gilang.dev/translator/v2/ratelimit"
+)
+
+// stubTranslator is an offline Translator used by tests.
+type stubTranslator struct {
+	calls int32
+	fn    func(text, from, to string) (*Translated, error)
+}
+
+func (s *stubTranslator) Translate(ctx context.Context, text, from, to string) (*Translated, error) {
+	atomic.AddInt32(&s.calls, 1)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if s.fn != nil {
+		return s.fn(text, from, to)
+	}
+	return &Translated{Text: strings.ToUpper(text)}, nil
+}
+
+func TestTranslateBatchWith(t *testing.T) {
+	stub := &stubTranslator{fn: func(text, from, to string) (*Translated, error) {
+		if text == "fail" {
+			return nil, errors.New("boom")
+		}
+		return &Translated{Text: strings.ToUpper(text)}, nil
+	}}
+
+	texts := []string{"one", "fail", "three", "four", "five", "six"}
+	results, err := TranslateBatchWith(context.Background(), stub, texts, "", params.INDONESIAN)
+	assert.NoError(t, err)
+	assert.Len(t, results, len(texts))
+	assert.Equal(t, int32(len(texts)), stub.calls)
+
+	for i, text := range texts {
+		if text == "fail" {
+			assert.Error(t, results[i].Err)
+			assert.Nil(t, results[i].Translated)
+			continue
+		}
+		assert.NoError(t, results[i].Err)
+		assert.Equal(t, strings.ToUpper(text), results[i].Translated.Text)
+	}
+}
+
+func TestTranslateBatchWithValidation(t *testing.T) {
+	stub := &stubTranslator{}
+	_, err := TranslateBatchWith(context.Background(), stub, nil, "auto", params.INDONESIAN)
+	assert.Error(t, err)
+	_, err = TranslateBatchWith(context.Background(), stub, []string{"a"}, "auto", "")
+	assert.Error(t, err)
+	_, err = TranslateBatchWith(context.Background(), stub, []string{"a"}, "not a language", params.INDONESIAN)
+	assert.Error(t, err)
+	assert.Zero(t, stub.calls)
+}
+
+func TestTranslateBatchCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := TranslateBatchWith(ctx, &stubTranslator{}, []string{"a", "b", "c"}, "en", params.INDONESIAN)
+	assert.NoError(t, err)
+	for _, result := range results {
+		assert.ErrorIs(t, result.Err, context.Canceled)
+	}
+}
+
+func TestDeepLBatchKeepsGroupErrors(t *testing.T) {
+	client := deepl.New(deepl.WithMaxChars(5), deepl.WithRateLimiter(ratelimit.New(ratelimit.WithRequestsPerSecond(1, 1))))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Every group fails before sending its request, each on its own
+	results, err := (&deeplAdapter{client: client}).TranslateBatch(ctx, []string{"hello", "world"}, "en", "de")
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	for _, result := range results {
+		assert.ErrorIs(t, result.Err, context.Canceled)
+		assert.Nil(t, result.Translated)
+	}
+}
@@ -0,0 +1,266 @@
+package gt
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gopkg.gilang.dev/translator/v2/errs"
+)
+
+const (
+	DefaultBreakerFailureThreshold = 5
+	DefaultBreakerOpenTimeout      = 30 * time.Second
+)
+
+// BreakerState represents the state of a circuit breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed lets every call through.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen fails every call with ErrCircuitOpen.
+	BreakerOpen
+	// BreakerHalfOpen lets a single probe call through.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitBreakerTranslator stops calling a failing translator for a while.
+type CircuitBreakerTranslator struct {
+	translator  Translator
+	threshold   int
+	ratio       float64
+	window      time.Duration
+	minRequests int
+	openTimeout time.Duration
+	failureIf   func(error) bool
+	now         func() time.Time
+
+	mu          sync.Mutex
+	state       BreakerState
+	consecutive int
+	outcomes    []breakerOutcome
+	openedAt    time.Time
+	probing     bool
+	backend     string
+	generation  uint64 // Changes with the state, to ignore outcomes of older states
+}
+
+type breakerOutcome struct {
+	at     time.Time
+	failed bool
+}
+
+// BreakerOption is a functional option for configuring NewCircuitBreakerTranslator.
+type BreakerOption func(*CircuitBreakerTranslator)
+
+// WithFailureThreshold opens the circuit after n consecutive failures.
+// Zero disables the threshold.
+func WithFailureThreshold(n int) BreakerOption {
+	return func(b *CircuitBreakerTranslator) {
+		b.threshold = n
+	}
+}
+
+// WithFailureRatio opens the circuit when at least ratio of the calls made
+// within window failed, once at least minRequests calls were made.
+func WithFailureRatio(ratio float64, window time.Duration, minRequests int) BreakerOption {
+	return func(b *CircuitBreakerTranslator) {
+		b.ratio = ratio
+		b.window = window
+		b.minRequests = minRequests
+	}
+}
+
+// WithOpenTimeout sets how long the circuit stays open before a probe is let through.
+func WithOpenTimeout(d time.Duration) BreakerOption {
+	return func(b *CircuitBreakerTranslator) {
+		b.openTimeout = d
+	}
+}
+
+// WithFailureIf sets the predicate deciding whether an error counts as a failure.
+func WithFailureIf(failureIf func(error) bool) BreakerOption {
+	return func(b *CircuitBreakerTranslator) {
+		b.failureIf = failureIf
+	}
+}
+
+// NewCircuitBreakerTranslator creates a Translator that opens its circuit
+// after repeated failures of translator (see IsRetryable), failing fast with
+// ErrCircuitOpen until the open timeout elapses. Then a single probe call is
+// let through: its success closes the circuit, its failure opens it again.
+func NewCircuitBreakerTranslator(translator Translator, opts ...BreakerOption) *CircuitBreakerTranslator {
+	b := &CircuitBreakerTranslator{
+		translator:  translator,
+		threshold:   DefaultBreakerFailureThreshold,
+		openTimeout: DefaultBreakerOpenTimeout,
+		failureIf:   IsRetryable,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// CircuitBreaker is a Middleware version of NewCircuitBreakerTranslator.
+func CircuitBreaker(opts ...BreakerOption) Middleware {
+	return func(next Translator) Translator {
+		return NewCircuitBreakerTranslator(next, opts...)
+	}
+}
+
+// State returns the current state of the circuit.
+func (b *CircuitBreakerTranslator) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.openTimeout {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+func (b *CircuitBreakerTranslator) Translate(ctx context.Context, text, from, to string) (*Translated, error) {
+	generation, err := b.allow()
+	if err != nil {
+		return nil, err
+	}
+	result, err := b.translator.Translate(ctx, text, from, to)
+	b.record(generation, err)
+	return result, err
+}
+
+// TranslateBatch implements BatchTranslator. A batch counts as a single call,
+// failed when the whole batch or every one of its texts failed.
+func (b *CircuitBreakerTranslator) TranslateBatch(ctx context.Context, texts []string, from, to string) ([]BatchResult, error) {
+	generation, err := b.allow()
+	if err != nil {
+		return nil, err
+	}
+	results, err := batchWith(ctx, b.translator, texts, from, to)
+	outcome := err
+	if err == nil {
+		for _, result := range results {
+			if result.Err == nil {
+				outcome = nil
+				break
+			}
+			outcome = result.Err
+		}
+	}
+	b.record(generation, outcome)
+	return results, err
+}
+
+// allow returns ErrCircuitOpen if the call must not go through, or else the
+// generation of the state the call starts in, to be passed to record.
+func (b *CircuitBreakerTranslator) allow() (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		elapsed := b.now().Sub(b.openedAt)
+		if elapsed < b.openTimeout {
+			e := errs.New(ErrCircuitOpen, b.backend, 0, "", nil)
+			e.RetryAfter = b.openTimeout - elapsed
+			return 0, e
+		}
+		b.setState(BreakerHalfOpen)
+		b.probing = true
+	case BreakerHalfOpen:
+		if b.probing {
+			return 0, errs.New(ErrCircuitOpen, b.backend, 0, "circuit breaker is half-open", nil)
+		}
+		b.probing = true
+	}
+	return b.generation, nil
+}
+
+// record updates the circuit with the outcome of a call started in generation.
+// Outcomes of calls started before the last state change are ignored, so that
+// a call allowed while closed neither counts as the probe nor opens the
+// circuit again.
+func (b *CircuitBreakerTranslator) record(generation uint64, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if generation != b.generation || b.state == BreakerOpen {
+		return
+	}
+
+	failed := err != nil && b.failureIf(err)
+	now := b.now()
+
+	if b.state == BreakerHalfOpen {
+		b.probing = false
+		if failed {
+			b.open(now, err)
+		} else {
+			b.setState(BreakerClosed)
+			b.consecutive = 0
+			b.outcomes = nil
+		}
+		return
+	}
+
+	if failed {
+		b.consecutive++
+	} else {
+		b.consecutive = 0
+	}
+
+	if b.window > 0 {
+		b.outcomes = append(b.outcomes, breakerOutcome{at: now, failed: failed})
+		cutoff := now.Add(-b.window)
+		for len(b.outcomes) > 0 && b.outcomes[0].at.Before(cutoff) {
+			b.outcomes = b.outcomes[1:]
+		}
+	}
+
+	if failed && (b.threshold > 0 && b.consecutive >= b.threshold || b.ratioExceeded()) {
+		b.open(now, err)
+	}
+}
+
+// ratioExceeded reports whether the failure ratio within the window is reached.
+func (b *CircuitBreakerTranslator) ratioExceeded() bool {
+	if b.ratio <= 0 || len(b.outcomes) == 0 || len(b.outcomes) < b.minRequests {
+		return false
+	}
+	failures := 0
+	for _, outcome := range b.outcomes {
+		if outcome.failed {
+			failures++
+		}
+	}
+	return float64(failures)/float64(len(b.outcomes)) >= b.ratio
+}
+
+// open opens the circuit after err.
+func (b *CircuitBreakerTranslator) open(now time.Time, err error) {
+	b.setState(BreakerOpen)
+	b.openedAt = now
+	b.consecutive = 0
+	b.outcomes = nil
+	b.backend = errs.Backend(err)
+}
+
+// setState moves the circuit to state, starting a new generation.
+func (b *CircuitBreakerTranslator) setState(state BreakerState) {
+	b.state = state
+	b.generation++
+}
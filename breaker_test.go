@@ -0,0 +1,117 @@
+package gt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.gilang.dev/translator/v2/errs"
+)
+
+func TestCircuitBreakerOpensAndProbes(t *testing.T) {
+	failing := true
+	stub := &stubTranslator{fn: func(text, from, to string) (*Translated, error) {
+		if failing {
+			return nil, errs.New(ErrNetwork, "stub", 0, "", nil)
+		}
+		return &Translated{Text: text}, nil
+	}}
+
+	now := time.Now()
+	breaker := NewCircuitBreakerTranslator(stub, WithFailureThreshold(2), WithOpenTimeout(time.Minute))
+	breaker.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		_, err := breaker.Translate(ctx, "hello", "en", "id")
+		assert.ErrorIs(t, err, ErrNetwork)
+	}
+	assert.Equal(t, BreakerOpen, breaker.State())
+
+	_, err := breaker.Translate(ctx, "hello", "en", "id")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, "stub", errs.Backend(err))
+	assert.Equal(t, int32(2), stub.calls)
+
+	// After the timeout a failing probe opens the circuit again
+	now = now.Add(time.Minute)
+	assert.Equal(t, BreakerHalfOpen, breaker.State())
+	_, err = breaker.Translate(ctx, "hello", "en", "id")
+	assert.ErrorIs(t, err, ErrNetwork)
+	assert.Equal(t, BreakerOpen, breaker.State())
+
+	// A successful probe closes it
+	now = now.Add(time.Minute)
+	failing = false
+	_, err = breaker.Translate(ctx, "hello", "en", "id")
+	assert.NoError(t, err)
+	assert.Equal(t, BreakerClosed, breaker.State())
+}
+
+func TestCircuitBreakerSingleProbe(t *testing.T) {
+	breaker := NewCircuitBreakerTranslator(&stubTranslator{}, WithOpenTimeout(0))
+	breaker.state = BreakerHalfOpen
+
+	_, err := breaker.allow()
+	assert.NoError(t, err)
+	_, err = breaker.allow()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+}
+
+func TestCircuitBreakerStaleOutcomes(t *testing.T) {
+	now := time.Now()
+	breaker := NewCircuitBreakerTranslator(&stubTranslator{}, WithFailureThreshold(1), WithOpenTimeout(time.Minute))
+	breaker.now = func() time.Time { return now }
+	failure := errs.New(ErrNetwork, "stub", 0, "", nil)
+
+	// Two calls start while closed; the first failure opens the circuit
+	slow, err := breaker.allow()
+	assert.NoError(t, err)
+	late, err := breaker.allow()
+	assert.NoError(t, err)
+	breaker.record(slow, failure)
+	assert.Equal(t, BreakerOpen, breaker.State())
+
+	// A late failure doesn't push the recovery back
+	now = now.Add(30 * time.Second)
+	breaker.record(late, failure)
+	now = now.Add(30 * time.Second)
+	assert.Equal(t, BreakerHalfOpen, breaker.State())
+
+	// Nor does a late success count as the probe result
+	probe, err := breaker.allow()
+	assert.NoError(t, err)
+	breaker.record(slow, nil)
+	assert.Equal(t, BreakerHalfOpen, breaker.State())
+	breaker.record(probe, nil)
+	assert.Equal(t, BreakerClosed, breaker.State())
+}
+
+func TestCircuitBreakerFailureRatio(t *testing.T) {
+	calls := 0
+	stub := &stubTranslator{fn: func(text, from, to string) (*Translated, error) {
+		calls++
+		if calls%2 == 0 {
+			return nil, errs.FromStatus("stub", 429, "")
+		}
+		return &Translated{Text: text}, nil
+	}}
+
+	breaker := NewCircuitBreakerTranslator(stub, WithFailureThreshold(0), WithFailureRatio(0.5, time.Minute, 4))
+	for i := 0; i < 3; i++ {
+		breaker.Translate(context.Background(), "hello", "en", "id")
+	}
+	assert.Equal(t, BreakerClosed, breaker.State())
+	breaker.Translate(context.Background(), "hello", "en", "id")
+	assert.Equal(t, BreakerOpen, breaker.State())
+}
+
+func TestCircuitBreakerIgnoresClientErrors(t *testing.T) {
+	stub := &stubTranslator{fn: func(text, from, to string) (*Translated, error) {
+		return nil, errs.New(ErrInvalidLanguage, "stub", 0, "", nil)
+	}}
+	breaker := NewCircuitBreakerTranslator(stub, WithFailureThreshold(1))
+	breaker.Translate(context.Background(), "hello", "en", "xx")
+	assert.Equal(t, BreakerClosed, breaker.State())
+}
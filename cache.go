@@ -0,0 +1,176 @@
+package gt
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Store persists cached translations. Implementations must be safe for
+// concurrent use. See the cache package for built-in stores.
+type Store interface {
+	// Get returns the translation stored under key, if any.
+	Get(key string) (*Translated, bool)
+	// Set stores a translation under key.
+	Set(key string, value *Translated) error
+}
+
+// CacheKey identifies a cached translation.
+type CacheKey struct {
+	Backend string
+	From    string
+	To      string
+	Text    string
+	Options string
+}
+
+// String returns a fixed-length digest of the key, suitable as a Store key.
+func (k CacheKey) String() string {
+	h := sha256.New()
+	for _, part := range []string{k.Backend, k.From, k.To, k.Options, k.Text} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cacheTranslator serves translations from a Store before calling the translator.
+type cacheTranslator struct {
+	translator Translator
+	store      Store
+	backend    string
+}
+
+// CacheOption is a functional option for configuring NewCacheTranslator.
+type CacheOption func(*cacheTranslator)
+
+// WithCacheBackend sets the backend name used in cache keys. It defaults to the
+// types of the backends behind the translator, in fallback order, so set it
+// when several instances share one store but are configured differently.
+func WithCacheBackend(name string) CacheOption {
+	return func(c *cacheTranslator) {
+		c.backend = name
+	}
+}
+
+// NewCacheTranslator creates a Translator that answers from store when possible
+// and stores every successful translation of translator.
+//
+// The default backend name of the keys tells the types of the translators
+// apart, not their settings: translators wrapped by TransformInput or
+// PostProcess with different functions, or clients of one backend configured
+// differently, share their entries. Give each of them its own name with
+// WithCacheBackend when they share a store.
+func NewCacheTranslator(translator Translator, store Store, opts ...CacheOption) Translator {
+	c := &cacheTranslator{
+		translator: translator,
+		store:      store,
+		backend:    backendName(translator),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *cacheTranslator) key(ctx context.Context, text, from, to string) string {
+	return CacheKey{Backend: c.backend, From: from, To: to, Text: text, Options: requestOptionsKey(ctx)}.String()
+}
+
+func (c *cacheTranslator) Translate(ctx context.Context, text, from, to string) (*Translated, error) {
+	key := c.key(ctx, text, from, to)
+	if cached, ok := c.store.Get(key); ok {
+		return cloneTranslated(cached), nil
+	}
+
+	result, err := c.translator.Translate(ctx, text, from, to)
+	if err != nil {
+		return nil, err
+	}
+	_ = c.store.Set(key, cloneTranslated(result))
+	return result, nil
+}
+
+// TranslateBatch implements BatchTranslator, sending only cache misses to the translator.
+func (c *cacheTranslator) TranslateBatch(ctx context.Context, texts []string, from, to string) ([]BatchResult, error) {
+	results := make([]BatchResult, len(texts))
+	keys := make([]string, len(texts))
+	var (
+		misses []int
+		batch  []string
+	)
+	for i, text := range texts {
+		keys[i] = c.key(ctx, text, from, to)
+		if cached, ok := c.store.Get(keys[i]); ok {
+			results[i].Translated = cloneTranslated(cached)
+			continue
+		}
+		misses = append(misses, i)
+		batch = append(batch, text)
+	}
+	if len(misses) == 0 {
+		return results, nil
+	}
+
+	batchResults, err := batchWith(ctx, c.translator, batch, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for n, i := range misses {
+		results[i] = batchResults[n]
+		if results[i].Err == nil && results[i].Translated != nil {
+			_ = c.store.Set(keys[i], cloneTranslated(results[i].Translated))
+		}
+	}
+	return results, nil
+}
+
+// cloneTranslated returns a copy of t sharing no slice or pointer with it, so
+// that callers can't change cached entries.
+func cloneTranslated(t *Translated) *Translated {
+	clone := *t
+	if t.Alternatives != nil {
+		clone.Alternatives = append([]string(nil), t.Alternatives...)
+	}
+	if t.Pronunciation != nil {
+		pronunciation := *t.Pronunciation
+		clone.Pronunciation = &pronunciation
+	}
+	if t.From.Text.Value != nil {
+		value := *t.From.Text.Value
+		clone.From.Text.Value = &value
+	}
+	return &clone
+}
+
+// backendName names the backends behind translator for cache keys: their types
+// in fallback order, seen through the wrappers that don't change translations.
+func backendName(translator Translator) string {
+	switch t := translator.(type) {
+	case *fallbackTranslator:
+		names := make([]string, len(t.translators))
+		for i, translator := range t.translators {
+			names[i] = backendName(translator)
+		}
+		return strings.Join(names, ",")
+	case *retryTranslator:
+		return backendName(t.translator)
+	case *rateLimitTranslator:
+		return backendName(t.translator)
+	case *CircuitBreakerTranslator:
+		return backendName(t.translator)
+	case *chunkTranslator:
+		return backendName(t.translator)
+	case *loggingTranslator:
+		return backendName(t.next)
+	case *timingTranslator:
+		return backendName(t.next)
+	case *inputTranslator:
+		return fmt.Sprintf("%T(%s)", t, backendName(t.next))
+	case *outputTranslator:
+		return fmt.Sprintf("%T(%s)", t, backendName(t.next))
+	}
+	return fmt.Sprintf("%T", translator)
+}
@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gt "gopkg.gilang.dev/translator/v2"
+)
+
+func TestLRUEviction(t *testing.T) {
+	c := NewLRU(2, 0)
+	c.Set("a", &gt.Translated{Text: "A"})
+	c.Set("b", &gt.Translated{Text: "B"})
+
+	// Touch "a" so that "b" becomes the least recently used
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("Expected a to be cached")
+	}
+	c.Set("c", &gt.Translated{Text: "C"})
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("Expected b to be evicted")
+	}
+	if v, ok := c.Get("a"); !ok || v.Text != "A" {
+		t.Error("Expected a to be kept")
+	}
+	if c.Len() != 2 {
+		t.Errorf("Expected 2 entries, got %d", c.Len())
+	}
+}
+
+func TestLRUTTL(t *testing.T) {
+	now := time.Now()
+	c := NewLRU(10, time.Minute)
+	c.now = func() time.Time { return now }
+	c.Set("a", &gt.Translated{Text: "A"})
+
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("Expected a to be cached")
+	}
+	now = now.Add(2 * time.Minute)
+	if _, ok := c.Get("a"); ok {
+		t.Error("Expected a to be expired")
+	}
+	if c.Len() != 0 {
+		t.Errorf("Expected expired entry to be removed, got %d entries", c.Len())
+	}
+}
+
+func TestFilePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.jsonl")
+
+	c, err := NewFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set("a", &gt.Translated{Text: "A"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set("a", &gt.Translated{Text: "A2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a line truncated by a crash
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"key":"b","val`)
+	f.Close()
+
+	c, err = NewFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if v, ok := c.Get("a"); !ok || v.Text != "A2" {
+		t.Errorf("Expected A2, got %v", v)
+	}
+	if c.Len() != 1 {
+		t.Errorf("Expected 1 entry, got %d", c.Len())
+	}
+
+	// The next translation doesn't land on the truncated line
+	if err := c.Set("c", &gt.Translated{Text: "C"}); err != nil {
+		t.Fatal(err)
+	}
+	c.Close()
+	c, err = NewFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if v, ok := c.Get("c"); !ok || v.Text != "C" {
+		t.Errorf("Expected C, got %v", v)
+	}
+}
+
+func TestFileUnterminatedLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.jsonl")
+	if err := os.WriteFile(path, []byte(`{"key":"a","value":{"text":"A"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A complete last line without line break is kept, and ended
+	c, err := NewFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set("b", &gt.Translated{Text: "B"}); err != nil {
+		t.Fatal(err)
+	}
+	c.Close()
+	c, err = NewFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if c.Len() != 2 {
+		t.Errorf("Expected 2 entries, got %d", c.Len())
+	}
+}
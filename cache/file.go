@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sync"
+
+	gt "gopkg.gilang.dev/translator/v2"
+)
+
+// File is a concurrency-safe store backed by an append-only file of JSON
+// lines, so cached translations survive restarts. All translations are also
+// kept in memory; when a key is stored more than once the last line wins.
+type File struct {
+	mu      sync.RWMutex
+	file    *os.File
+	entries map[string]*gt.Translated
+}
+
+type fileEntry struct {
+	Key   string         `json:"key"`
+	Value *gt.Translated `json:"value"`
+}
+
+// NewFile opens or creates the store at path and loads its translations.
+// Lines that can't be decoded are skipped, and a last line truncated by a
+// crash is cut off the file so that the next translation starts a line.
+func NewFile(path string) (*File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string]*gt.Translated)
+	load := func(line []byte) bool {
+		var entry fileEntry
+		if err := json.Unmarshal(line, &entry); err != nil || entry.Value == nil {
+			return false
+		}
+		entries[entry.Key] = entry.Value
+		return true
+	}
+
+	var (
+		reader = bufio.NewReader(f)
+		line   []byte
+		end    int64 // End of the last complete line
+	)
+	for {
+		if line, err = reader.ReadBytes('\n'); err != nil {
+			break
+		}
+		end += int64(len(line))
+		load(line)
+	}
+	if err == io.EOF {
+		// The last line, if any, has no line break
+		switch {
+		case len(line) == 0:
+			err = nil
+		case load(line):
+			_, err = f.Write([]byte{'\n'})
+		default:
+			err = f.Truncate(end)
+		}
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &File{file: f, entries: entries}, nil
+}
+
+// Get returns the translation stored under key, if any.
+func (c *File) Get(key string) (*gt.Translated, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	value, ok := c.entries[key]
+	return value, ok
+}
+
+// Set stores a translation under key and appends it to the file.
+func (c *File) Set(key string, value *gt.Translated) error {
+	line, err := json.Marshal(fileEntry{Key: key, Value: value})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.file == nil {
+		return errors.New("cache: file store is closed")
+	}
+	if _, err := c.file.Write(line); err != nil {
+		return err
+	}
+	c.entries[key] = value
+	return nil
+}
+
+// Len returns the number of stored translations.
+func (c *File) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.entries)
+}
+
+// Close closes the underlying file. Stored translations remain readable.
+func (c *File) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.file == nil {
+		return nil
+	}
+	err := c.file.Close()
+	c.file = nil
+	return err
+}
@@ -0,0 +1,95 @@
+// Package cache provides Store implementations for gt.NewCacheTranslator.
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	gt "gopkg.gilang.dev/translator/v2"
+)
+
+// LRU is a concurrency-safe, size-bounded in-memory store that evicts the
+// least recently used translation when full.
+type LRU struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	entries map[string]*list.Element
+	order   *list.List
+	now     func() time.Time
+}
+
+type lruEntry struct {
+	key     string
+	value   *gt.Translated
+	expires time.Time
+}
+
+// NewLRU creates an LRU store holding at most size translations, each kept for
+// at most ttl. A zero ttl keeps translations until they are evicted.
+func NewLRU(size int, ttl time.Duration) *LRU {
+	if size < 1 {
+		size = 1
+	}
+	return &LRU{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		now:     time.Now,
+	}
+}
+
+// Get returns the translation stored under key, if present and not expired.
+func (c *LRU) Get(key string) (*gt.Translated, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*lruEntry)
+	if !entry.expires.IsZero() && c.now().After(entry.expires) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+// Set stores a translation under key, evicting the least recently used one if needed.
+func (c *LRU) Set(key string, value *gt.Translated) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expires time.Time
+	if c.ttl > 0 {
+		expires = c.now().Add(c.ttl)
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*lruEntry)
+		entry.value = value
+		entry.expires = expires
+		c.order.MoveToFront(elem)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value, expires: expires})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+	return nil
+}
+
+// Len returns the number of stored translations, including expired ones not yet evicted.
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
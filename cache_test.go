@@ -0,0 +1,101 @@
+package gt
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mapStore is a minimal Store used by tests.
+type mapStore struct {
+	mu      sync.Mutex
+	entries map[string]*Translated
+}
+
+func (m *mapStore) Get(key string) (*Translated, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value, ok := m.entries[key]
+	return value, ok
+}
+
+func (m *mapStore) Set(key string, value *Translated) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = value
+	return nil
+}
+
+func TestCacheTranslator(t *testing.T) {
+	stub := &stubTranslator{}
+	translator := NewCacheTranslator(stub, &mapStore{entries: map[string]*Translated{}})
+
+	for i := 0; i < 3; i++ {
+		result, err := translator.Translate(context.Background(), "hello", "en", "id")
+		assert.NoError(t, err)
+		assert.Equal(t, "HELLO", result.Text)
+	}
+	assert.Equal(t, int32(1), stub.calls)
+
+	_, err := translator.Translate(context.Background(), "hello", "en", "fr")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), stub.calls)
+}
+
+func TestCacheTranslatorBatch(t *testing.T) {
+	stub := &stubTranslator{}
+	translator := NewCacheTranslator(stub, &mapStore{entries: map[string]*Translated{}})
+
+	_, err := translator.Translate(context.Background(), "one", "en", "id")
+	assert.NoError(t, err)
+
+	results, err := TranslateBatchWith(context.Background(), translator, []string{"one", "two"}, "en", "id")
+	assert.NoError(t, err)
+	assert.Equal(t, "ONE", results[0].Translated.Text)
+	assert.Equal(t, "TWO", results[1].Translated.Text)
+	assert.Equal(t, int32(2), stub.calls)
+}
+
+func TestCacheKey(t *testing.T) {
+	a := CacheKey{Backend: "google", From: "en", To: "id", Text: "hello"}
+	b := CacheKey{Backend: "deepl", From: "en", To: "id", Text: "hello"}
+	assert.NotEqual(t, a.String(), b.String())
+	assert.Equal(t, a.String(), CacheKey{Backend: "google", From: "en", To: "id", Text: "hello"}.String())
+}
+
+func TestCacheTranslatorCopies(t *testing.T) {
+	stub := &stubTranslator{fn: func(text, from, to string) (*Translated, error) {
+		return &Translated{Text: text, Alternatives: []string{"a", "b"}}, nil
+	}}
+	translator := NewCacheTranslator(stub, &mapStore{entries: map[string]*Translated{}})
+
+	result, err := translator.Translate(context.Background(), "hello", "en", "id")
+	assert.NoError(t, err)
+	result.Alternatives[0] = "changed"
+
+	for i := 0; i < 2; i++ {
+		cached, err := translator.Translate(context.Background(), "hello", "en", "id")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, cached.Alternatives)
+		cached.Alternatives[1] = "changed"
+	}
+
+	results, err := TranslateBatchWith(context.Background(), translator, []string{"hello"}, "en", "id")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, results[0].Translated.Alternatives)
+	assert.Equal(t, int32(1), stub.calls)
+}
+
+func TestCacheTranslatorBackendKey(t *testing.T) {
+	store := &mapStore{entries: map[string]*Translated{}}
+	google := &googleTranslateAdapter{}
+	deepl := &deeplAdapter{}
+	assert.Equal(t, "*gt.googleTranslateAdapter,*gt.deeplAdapter",
+		backendName(NewFallbackTranslator(NewRetryTranslator(google), deepl)))
+	assert.NotEqual(t,
+		NewCacheTranslator(NewFallbackTranslator(google, deepl), store).(*cacheTranslator).backend,
+		NewCacheTranslator(NewFallbackTranslator(deepl, google), store).(*cacheTranslator).backend)
+	assert.Equal(t, "custom", NewCacheTranslator(google, store, WithCacheBackend("custom")).(*cacheTranslator).backend)
+}
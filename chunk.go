@@ -0,0 +1,275 @@
+package gt
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultChunkConcurrency is the number of chunks of a text translated in parallel.
+const DefaultChunkConcurrency = 4
+
+// chunkBoundaries split texts from the coarsest boundary to the finest:
+// paragraphs, lines, sentences, then words. Each pattern matches the
+// whitespace ending a piece.
+var chunkBoundaries = []*regexp.Regexp{
+	regexp.MustCompile(`\n[ \t\r]*\n\s*`),
+	regexp.MustCompile(`\n\s*`),
+	regexp.MustCompile(`[.!?…]+["'”’»)\]]*\s+|[。！？]+\s*`),
+	regexp.MustCompile(`\s+`),
+}
+
+// chunkTranslator splits long texts before translating them.
+type chunkTranslator struct {
+	translator  Translator
+	maxChars    int
+	concurrency int
+}
+
+// ChunkOption is a functional option for configuring NewChunkTranslator.
+type ChunkOption func(*chunkTranslator)
+
+// WithChunkConcurrency sets how many chunks of a text are translated in
+// parallel. Use 1 for backends that don't allow parallel requests.
+func WithChunkConcurrency(n int) ChunkOption {
+	return func(c *chunkTranslator) {
+		c.concurrency = n
+	}
+}
+
+// NewChunkTranslator creates a Translator that splits texts longer than
+// maxChars characters on paragraph, line, sentence or word boundaries, the
+// coarsest possible, translates the chunks and joins them back with their
+// original whitespace. The Google and DeepL translators of this package
+// already split texts longer than their client's MaxChars.
+func NewChunkTranslator(translator Translator, maxChars int, opts ...ChunkOption) Translator {
+	c := &chunkTranslator{
+		translator:  translator,
+		maxChars:    maxChars,
+		concurrency: DefaultChunkConcurrency,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Chunk is a Middleware version of NewChunkTranslator.
+func Chunk(maxChars int, opts ...ChunkOption) Middleware {
+	return func(next Translator) Translator {
+		return NewChunkTranslator(next, maxChars, opts...)
+	}
+}
+
+func (c *chunkTranslator) Translate(ctx context.Context, text, from, to string) (*Translated, error) {
+	if c.maxChars <= 0 || utf8.RuneCountInString(text) <= c.maxChars {
+		return c.translator.Translate(ctx, text, from, to)
+	}
+	return translateChunks(ctx, c.translator, splitText(text, c.maxChars), from, to, c.concurrency)
+}
+
+// TranslateBatch implements BatchTranslator. Short texts are translated in a
+// single batch, long ones chunk by chunk.
+func (c *chunkTranslator) TranslateBatch(ctx context.Context, texts []string, from, to string) ([]BatchResult, error) {
+	return batchChunked(ctx, c.translator, texts, from, to, c.maxChars, c.concurrency)
+}
+
+// batchChunked translates the texts up to maxChars characters with batchWith
+// and the longer ones with translateChunks.
+func batchChunked(ctx context.Context, translator Translator, texts []string, from, to string, maxChars, concurrency int) ([]BatchResult, error) {
+	var (
+		results = make([]BatchResult, len(texts))
+		short   []string
+		indexes []int
+	)
+	for i, text := range texts {
+		if maxChars > 0 && utf8.RuneCountInString(text) > maxChars {
+			results[i].Translated, results[i].Err = translateChunks(ctx, translator, splitText(text, maxChars), from, to, concurrency)
+			continue
+		}
+		short = append(short, text)
+		indexes = append(indexes, i)
+	}
+	if len(short) == 0 {
+		return results, nil
+	}
+
+	batch, err := batchWith(ctx, translator, short, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for n, i := range indexes {
+		results[i] = batch[n]
+	}
+	return results, nil
+}
+
+// translateChunks translates chunks, which must each be short enough for
+// translator, and joins the results. Whitespace surrounding each chunk is
+// kept as is. When from is "auto", the language detected in the first chunk
+// is used for the others, so that they are all translated alike.
+func translateChunks(ctx context.Context, translator Translator, chunks []string, from, to string, concurrency int) (*Translated, error) {
+	spans, bodies, indexes := splitSpaces(chunks)
+	if len(bodies) == 0 {
+		return nil, errTextRequired()
+	}
+
+	first, err := translator.Translate(ctx, bodies[0], from, to)
+	if err != nil {
+		return nil, err
+	}
+	spans[indexes[0]].body = first.Text
+	if from == "auto" && first.From.Language.Iso != "" {
+		from = first.From.Language.Iso
+	}
+
+	for n, result := range translateParallel(ctx, translator, bodies[1:], from, to, concurrency) {
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		spans[indexes[n+1]].body = result.Translated.Text
+	}
+	return joinedResult(first, joinSpans(spans, ""), len(bodies)), nil
+}
+
+// textSpan is a text split into its body and the whitespace around it.
+type textSpan struct{ lead, body, trail string }
+
+// splitSpace splits text into a textSpan, whose body is empty when text is
+// only whitespace.
+func splitSpace(text string) textSpan {
+	body := strings.TrimSpace(text)
+	if body == "" {
+		return textSpan{lead: text}
+	}
+	start := strings.Index(text, body)
+	return textSpan{lead: text[:start], body: body, trail: text[start+len(body):]}
+}
+
+// splitSpaces splits every text with splitSpace, and returns the bodies to
+// translate along with the index of their text.
+func splitSpaces(texts []string) (spans []textSpan, bodies []string, indexes []int) {
+	spans = make([]textSpan, len(texts))
+	for i, text := range texts {
+		spans[i] = splitSpace(text)
+		if spans[i].body != "" {
+			bodies = append(bodies, spans[i].body)
+			indexes = append(indexes, i)
+		}
+	}
+	return spans, bodies, indexes
+}
+
+// joinSpans puts spans back together, separated by sep.
+func joinSpans(spans []textSpan, sep string) string {
+	var b strings.Builder
+	for i, s := range spans {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(s.lead)
+		b.WriteString(s.body)
+		b.WriteString(s.trail)
+	}
+	return b.String()
+}
+
+// joinedResult returns a copy of first, the translation of the first of parts
+// texts, with the joined translation text.
+func joinedResult(first *Translated, text string, parts int) *Translated {
+	result := *first
+	result.Text = text
+	if parts > 1 {
+		// Alternatives and pronunciation only describe the first part
+		result.Alternatives = nil
+		result.Pronunciation = nil
+	}
+	return &result
+}
+
+// splitText splits text into chunks of at most maxChars characters, cutting
+// on the coarsest boundary that makes them fit. Whitespace at a boundary ends
+// the chunk before it, and joining the chunks gives back text.
+func splitText(text string, maxChars int) []string {
+	if utf8.RuneCountInString(text) <= maxChars {
+		return []string{text}
+	}
+	for _, boundary := range chunkBoundaries {
+		pieces := splitAfter(text, boundary)
+		if len(pieces) < 2 {
+			continue
+		}
+
+		var (
+			chunks  []string
+			current string
+		)
+		for _, piece := range pieces {
+			if utf8.RuneCountInString(current)+utf8.RuneCountInString(piece) <= maxChars {
+				current += piece
+				continue
+			}
+			if current != "" {
+				chunks = append(chunks, current)
+				current = ""
+			}
+			if utf8.RuneCountInString(piece) > maxChars {
+				chunks = append(chunks, splitText(piece, maxChars)...)
+				continue
+			}
+			current = piece
+		}
+		if current != "" {
+			chunks = append(chunks, current)
+		}
+		return chunks
+	}
+	return splitRunes(text, maxChars)
+}
+
+// splitAfter splits text after every match of re.
+func splitAfter(text string, re *regexp.Regexp) []string {
+	var (
+		pieces []string
+		start  int
+	)
+	for _, match := range re.FindAllStringIndex(text, -1) {
+		if match[1] == start || match[1] == len(text) {
+			continue
+		}
+		pieces = append(pieces, text[start:match[1]])
+		start = match[1]
+	}
+	return append(pieces, text[start:])
+}
+
+// splitRunes splits text into chunks of maxChars characters, for words
+// longer than a chunk.
+func splitRunes(text string, maxChars int) []string {
+	var chunks []string
+	for utf8.RuneCountInString(text) > maxChars {
+		end := 0
+		for i := 0; i < maxChars; i++ {
+			_, size := utf8.DecodeRuneInString(text[end:])
+			end += size
+		}
+		// Don't cut between a letter and its combining marks
+		for cut := end; cut > 0; {
+			r, _ := utf8.DecodeRuneInString(text[cut:])
+			if !unicode.Is(unicode.Mn, r) {
+				end = cut
+				break
+			}
+			_, size := utf8.DecodeLastRuneInString(text[:cut])
+			cut -= size
+		}
+		chunks = append(chunks, text[:end])
+		text = text[end:]
+	}
+	if text != "" {
+		chunks = append(chunks, text)
+	}
+	return chunks
+}
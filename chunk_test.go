@@ -0,0 +1,116 @@
+package gt
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxChars int
+		want     []string
+	}{
+		{"short", "Hello world.", 20, []string{"Hello world."}},
+		{"paragraphs", "First one.\n\nSecond one.\n\n  Third.", 15, []string{"First one.\n\n", "Second one.\n\n  ", "Third."}},
+		{"paragraphs packed", "A b.\n\nC d.\n\nE f.", 12, []string{"A b.\n\nC d.\n\n", "E f."}},
+		{"lines", "Line one\nLine two\nLine three", 10, []string{"Line one\n", "Line two\n", "Line three"}},
+		{"sentences", "One. Two! Three? Four.", 10, []string{"One. Two! ", "Three? ", "Four."}},
+		{"cjk sentences", "今日は。明日は。", 4, []string{"今日は。", "明日は。"}},
+		{"words", "alpha beta gamma delta", 11, []string{"alpha beta ", "gamma delta"}},
+		{"runes", "abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+		{"combining marks", "abcdéfg", 5, []string{"abcd", "éfg"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := splitText(tt.text, tt.maxChars)
+			assert.Equal(t, tt.want, chunks)
+			assert.Equal(t, tt.text, strings.Join(chunks, ""))
+		})
+	}
+}
+
+func TestSplitTextLimits(t *testing.T) {
+	text := strings.Repeat("Lorem ipsum dolor sit amet, consectetur adipiscing elit. ", 40) +
+		"\n\n" + strings.Repeat("Sed do eiusmod tempor incididunt ut labore.\n", 30) +
+		strings.Repeat("x", 250)
+	for _, maxChars := range []int{50, 100, 500, 1000} {
+		chunks := splitText(text, maxChars)
+		assert.Equal(t, text, strings.Join(chunks, ""))
+		for _, chunk := range chunks {
+			assert.LessOrEqual(t, utf8.RuneCountInString(chunk), maxChars)
+		}
+	}
+}
+
+func TestChunkTranslator(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		calls []string
+		froms []string
+	)
+	translator := funcTranslator(func(ctx context.Context, text, from, to string) (*Translated, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, text)
+		froms = append(froms, from)
+		pronunciation := "p"
+		result := &Translated{Text: strings.ToUpper(text), Pronunciation: &pronunciation, Alternatives: []string{text}}
+		result.From.Language.Iso = "en"
+		return result, nil
+	})
+
+	text := "  First paragraph.\n\nSecond paragraph.\n\n\tThird paragraph.\n"
+	result, err := NewChunkTranslator(translator, 20).Translate(context.Background(), text, "auto", "id")
+	require.NoError(t, err)
+	assert.Equal(t, "  FIRST PARAGRAPH.\n\nSECOND PARAGRAPH.\n\n\tTHIRD PARAGRAPH.\n", result.Text)
+	assert.Nil(t, result.Pronunciation)
+	assert.Nil(t, result.Alternatives)
+	assert.Equal(t, "en", result.From.Language.Iso)
+
+	assert.Equal(t, "First paragraph.", calls[0])
+	assert.ElementsMatch(t, []string{"First paragraph.", "Second paragraph.", "Third paragraph."}, calls)
+	assert.Equal(t, []string{"auto", "en", "en"}, froms)
+
+	calls = nil
+	result, err = NewChunkTranslator(translator, 20).Translate(context.Background(), "Short text", "en", "id")
+	require.NoError(t, err)
+	assert.Equal(t, "SHORT TEXT", result.Text)
+	assert.NotNil(t, result.Pronunciation)
+	assert.Equal(t, []string{"Short text"}, calls)
+}
+
+func TestChunkTranslatorError(t *testing.T) {
+	translator := funcTranslator(func(ctx context.Context, text, from, to string) (*Translated, error) {
+		if strings.HasPrefix(text, "Bad") {
+			return nil, ErrUnexpectedResponse
+		}
+		return &Translated{Text: text}, nil
+	})
+	_, err := NewChunkTranslator(translator, 10).Translate(context.Background(), "Good one.\nBad one.", "en", "id")
+	assert.ErrorIs(t, err, ErrUnexpectedResponse)
+}
+
+func TestChunkTranslatorBatch(t *testing.T) {
+	stub := &stubTranslator{}
+	results, err := TranslateBatchWith(context.Background(), Chain(stub, Chunk(10)), []string{"short", "a much longer text"}, "en", "id")
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "SHORT", results[0].Translated.Text)
+	assert.Equal(t, "A MUCH LONGER TEXT", results[1].Translated.Text)
+	assert.Equal(t, int32(4), stub.calls) // "short", then "a much ", "longer " and "text"
+}
+
+func TestGroupTexts(t *testing.T) {
+	texts := []string{"aaaa", "bbbb", "cc", "dddddddddddd", "e", "ffff"}
+	assert.Equal(t, [][]int{{0, 1, 2}, {3}, {4, 5}}, groupTexts(texts, 10))
+	assert.Equal(t, [][]int{{0, 1, 2, 3, 4, 5}}, groupTexts(texts, 0))
+	assert.Nil(t, groupTexts(nil, 10))
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	gt "gopkg.gilang.dev/translator/v2"
+)
+
+// LibreTranslate compatibility: the routes below accept and return the shapes
+// of the LibreTranslate API, so that its clients can use any gt.Translator.
+// Bodies are JSON or form-encoded; api_key is accepted and ignored.
+
+// ltRequest is the body of the LibreTranslate endpoints.
+type ltRequest struct {
+	Q            json.RawMessage `json:"q"` // A string, or an array of strings for /translate
+	Source       string          `json:"source"`
+	Target       string          `json:"target"`
+	Format       string          `json:"format"` // "text" or "html"
+	Alternatives int             `json:"alternatives"`
+	APIKey       string          `json:"api_key"`
+}
+
+// ltDetection is a detected language. Confidence is between 0 and 100.
+type ltDetection struct {
+	Confidence float64 `json:"confidence"`
+	Language   string  `json:"language"`
+}
+
+// ltTranslation is the response of /translate for a single text.
+type ltTranslation struct {
+	TranslatedText   string       `json:"translatedText"`
+	DetectedLanguage *ltDetection `json:"detectedLanguage,omitempty"`
+	Alternatives     []string     `json:"alternatives,omitempty"`
+}
+
+// ltLanguage is an entry of /languages.
+type ltLanguage struct {
+	Code    string   `json:"code"`
+	Name    string   `json:"name"`
+	Targets []string `json:"targets"`
+}
+
+func (s *server) libreTranslateRoutes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /translate", s.handleLTTranslate)
+	mux.HandleFunc("POST /detect", s.handleLTDetect)
+	mux.HandleFunc("GET /languages", s.handleLTLanguages)
+	return mux
+}
+
+func (s *server) handleLTTranslate(w http.ResponseWriter, r *http.Request) {
+	req, texts, batch, ok := s.decodeLT(w, r)
+	if !ok {
+		return
+	}
+	if req.Target == "" {
+		writeLTError(w, http.StatusBadRequest, "Invalid request: missing target parameter")
+		return
+	}
+	ctx, cancel := s.context(r)
+	defer cancel()
+
+	ctx = gt.ContextWithRequest(ctx, gt.TranslateRequest{
+		From:         req.Source,
+		To:           req.Target,
+		Alternatives: req.Alternatives,
+	})
+	results, err := s.translateLT(ctx, texts, req)
+	if err != nil {
+		writeLTErr(w, err)
+		return
+	}
+
+	detect := req.Source == "" || req.Source == "auto"
+	translations := make([]ltTranslation, len(results))
+	for i, result := range results {
+		if result.Err != nil {
+			writeLTErr(w, result.Err)
+			return
+		}
+		translations[i] = ltTranslation{TranslatedText: result.Translated.Text, Alternatives: result.Translated.Alternatives}
+		if iso := result.Translated.From.Language.Iso; detect && iso != "" {
+			// Backends don't score the detection made while translating
+			translations[i].DetectedLanguage = &ltDetection{Confidence: 100, Language: strings.ToLower(iso)}
+		}
+	}
+
+	if !batch {
+		writeJSON(w, http.StatusOK, translations[0])
+		return
+	}
+	response := struct {
+		TranslatedText   []string       `json:"translatedText"`
+		DetectedLanguage []*ltDetection `json:"detectedLanguage,omitempty"`
+		Alternatives     [][]string     `json:"alternatives,omitempty"`
+	}{}
+	for _, t := range translations {
+		response.TranslatedText = append(response.TranslatedText, t.TranslatedText)
+		if detect {
+			// One entry per text, even when the language is unknown
+			detected := t.DetectedLanguage
+			if detected == nil {
+				detected = &ltDetection{}
+			}
+			response.DetectedLanguage = append(response.DetectedLanguage, detected)
+		}
+		if req.Alternatives > 0 {
+			response.Alternatives = append(response.Alternatives, t.Alternatives)
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// translateLT translates texts in a single batch, or every HTML document with
+// gt.TranslateHTMLWith so that its markup is kept by any backend.
+func (s *server) translateLT(ctx context.Context, texts []string, req ltRequest) ([]gt.BatchResult, error) {
+	if req.Format != "html" {
+		return gt.TranslateBatchWith(ctx, s.translator, texts, req.Source, req.Target)
+	}
+	results := make([]gt.BatchResult, len(texts))
+	for i, text := range texts {
+		translated, err := gt.TranslateHTMLWith(ctx, s.translator, text, req.Source, req.Target)
+		if err != nil {
+			return nil, err
+		}
+		results[i].Translated = translated
+	}
+	return results, nil
+}
+
+func (s *server) handleLTDetect(w http.ResponseWriter, r *http.Request) {
+	_, texts, batch, ok := s.decodeLT(w, r)
+	if !ok {
+		return
+	}
+	if batch {
+		writeLTError(w, http.StatusBadRequest, "Invalid request: q must be a string")
+		return
+	}
+	ctx, cancel := s.context(r)
+	defer cancel()
+
+	detected, err := gt.DetectWith(ctx, s.detector, texts[0])
+	if err != nil {
+		writeLTErr(w, err)
+		return
+	}
+	detections := make([]ltDetection, len(detected))
+	for i, d := range detected {
+		detections[i] = ltDetection{Confidence: d.Confidence * 100, Language: d.Language}
+	}
+	writeJSON(w, http.StatusOK, detections)
+}
+
+func (s *server) handleLTLanguages(w http.ResponseWriter, r *http.Request) {
+	if s.lister == nil {
+		writeLTError(w, http.StatusNotImplemented, "The backend can't list its languages")
+		return
+	}
+	ctx, cancel := s.context(r)
+	defer cancel()
+
+	languages, err := gt.LanguagesWith(ctx, s.lister)
+	if err != nil {
+		writeLTErr(w, err)
+		return
+	}
+
+	var targets []string
+	for _, l := range languages {
+		if l.Target {
+			targets = append(targets, strings.ToLower(l.Code))
+		}
+	}
+	sort.Strings(targets)
+
+	var response []ltLanguage
+	for _, l := range languages {
+		if !l.Source {
+			continue
+		}
+		code := strings.ToLower(l.Code)
+		entry := ltLanguage{Code: code, Name: l.Name, Targets: []string{}}
+		for _, target := range targets {
+			if target != code {
+				entry.Targets = append(entry.Targets, target)
+			}
+		}
+		response = append(response, entry)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// decodeLT decodes a JSON or form-encoded LibreTranslate request and returns
+// its texts, and whether q was an array. It writes a 400 response on failure.
+func (s *server) decodeLT(w http.ResponseWriter, r *http.Request) (ltRequest, []string, bool, bool) {
+	var req ltRequest
+	r.Body = http.MaxBytesReader(w, r.Body, s.maxBody)
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/json" {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeLTDecodeError(w, err)
+			return req, nil, false, false
+		}
+	} else {
+		if err := r.ParseMultipartForm(s.maxBody); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+			writeLTDecodeError(w, err)
+			return req, nil, false, false
+		}
+		q, _ := json.Marshal(r.PostFormValue("q"))
+		req = ltRequest{
+			Q:      q,
+			Source: r.PostFormValue("source"),
+			Target: r.PostFormValue("target"),
+			Format: r.PostFormValue("format"),
+			APIKey: r.PostFormValue("api_key"),
+		}
+		req.Alternatives, _ = strconv.Atoi(r.PostFormValue("alternatives"))
+	}
+
+	var (
+		text  string
+		texts []string
+	)
+	switch {
+	case json.Unmarshal(req.Q, &text) == nil:
+		if text == "" {
+			break
+		}
+		return req, []string{text}, false, true
+	case json.Unmarshal(req.Q, &texts) == nil:
+		if len(texts) == 0 {
+			break
+		}
+		return req, texts, true, true
+	}
+	writeLTError(w, http.StatusBadRequest, "Invalid request: missing q parameter")
+	return req, nil, false, false
+}
+
+func writeLTDecodeError(w http.ResponseWriter, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		writeLTError(w, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	}
+	writeLTError(w, http.StatusBadRequest, "Invalid request: "+err.Error())
+}
+
+// writeLTErr writes err with the status of its class.
+func writeLTErr(w http.ResponseWriter, err error) {
+	_, status := classify(err)
+	setRetryAfter(w, err)
+	writeLTError(w, status, err.Error())
+}
+
+func writeLTError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gt "gopkg.gilang.dev/translator/v2"
+)
+
+func postLT(t *testing.T, url, contentType, body string) (int, string) {
+	resp, err := http.Post(url, contentType, strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(data)
+}
+
+func TestLibreTranslateTranslate(t *testing.T) {
+	ts := newTestServerAPI(t, "libretranslate")
+
+	status, body := postLT(t, ts.URL+"/translate", "application/json", `{"q": "hello", "source": "auto", "target": "id"}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"translatedText": "HELLO", "detectedLanguage": {"confidence": 100, "language": "en"}}`, body)
+
+	status, body = postLT(t, ts.URL+"/translate", "application/json", `{"q": ["hello", "bye"], "source": "en", "target": "id", "alternatives": 1}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"translatedText": ["HELLO", "BYE"], "alternatives": [["hello"], ["bye"]]}`, body)
+
+	form := url.Values{"q": {"hello"}, "source": {"en"}, "target": {"id"}, "api_key": {"ignored"}}
+	status, body = postLT(t, ts.URL+"/translate", "application/x-www-form-urlencoded", form.Encode())
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"translatedText": "HELLO"}`, body)
+}
+
+func TestLibreTranslateErrors(t *testing.T) {
+	ts := newTestServerAPI(t, "libretranslate")
+
+	tests := []struct {
+		body   string
+		status int
+	}{
+		{`{"source": "en", "target": "id"}`, http.StatusBadRequest},
+		{`{"q": "", "source": "en", "target": "id"}`, http.StatusBadRequest},
+		{`{"q": "hello", "source": "en"}`, http.StatusBadRequest},
+		{`{"q": "hello", "source": "en", "target": "not a language"}`, http.StatusBadRequest},
+		{`{"q": "rate", "source": "en", "target": "id"}`, http.StatusTooManyRequests},
+		{`{"q": `, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		status, body := postLT(t, ts.URL+"/translate", "application/json", tt.body)
+		assert.Equal(t, tt.status, status, tt.body)
+		var decoded map[string]string
+		assert.NoError(t, json.Unmarshal([]byte(body), &decoded))
+		assert.NotEmpty(t, decoded["error"])
+	}
+}
+
+func TestLibreTranslateDetect(t *testing.T) {
+	ts := newTestServerAPI(t, "libretranslate")
+
+	status, body := postLT(t, ts.URL+"/detect", "application/json", `{"q": "bonjour"}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `[{"confidence": 90, "language": "fr"}]`, body)
+
+	status, _ = postLT(t, ts.URL+"/detect", "application/x-www-form-urlencoded", "q=")
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestLibreTranslateLanguages(t *testing.T) {
+	ts := newTestServerAPI(t, "libretranslate")
+
+	resp, err := http.Get(ts.URL + "/languages")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `[
+		{"code": "en", "name": "English", "targets": ["en-us", "fr"]},
+		{"code": "fr", "name": "French", "targets": ["en-us"]}
+	]`, string(data))
+}
+
+// ltBatchTranslator upper-cases texts in batches, detecting English unless the
+// text is "?".
+type ltBatchTranslator struct {
+	batches int
+}
+
+func (b *ltBatchTranslator) Translate(ctx context.Context, text, from, to string) (*gt.Translated, error) {
+	result := &gt.Translated{Text: strings.ToUpper(text)}
+	if text != "?" {
+		result.From.Language.Iso = "en"
+	}
+	return result, nil
+}
+
+func (b *ltBatchTranslator) TranslateBatch(ctx context.Context, texts []string, from, to string) ([]gt.BatchResult, error) {
+	b.batches++
+	results := make([]gt.BatchResult, len(texts))
+	for i, text := range texts {
+		results[i].Translated, _ = b.Translate(ctx, text, from, to)
+	}
+	return results, nil
+}
+
+func TestLibreTranslateBatch(t *testing.T) {
+	translator := &ltBatchTranslator{}
+	s := &server{translator: translator, detector: gt.NewOfflineDetector(), maxBody: 1 << 10}
+	ts := httptest.NewServer(s.libreTranslateRoutes())
+	defer ts.Close()
+
+	status, body := postLT(t, ts.URL+"/translate", "application/json", `{"q": ["hello", "?", "bye"], "source": "auto", "target": "id"}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{
+		"translatedText": ["HELLO", "?", "BYE"],
+		"detectedLanguage": [
+			{"confidence": 100, "language": "en"},
+			{"confidence": 0, "language": ""},
+			{"confidence": 100, "language": "en"}
+		]
+	}`, body)
+	assert.Equal(t, 1, translator.batches)
+}
+
+func TestLibreTranslateHTML(t *testing.T) {
+	ts := newTestServerAPI(t, "libretranslate")
+
+	status, body := postLT(t, ts.URL+"/translate", "application/json", `{"q": "<p>hello <code>x</code></p>", "source": "en", "target": "id", "format": "html"}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"translatedText": "<p>HELLO <code>x</code></p>"}`, body)
+}
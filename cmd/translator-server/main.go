@@ -0,0 +1,150 @@
+// Command translator-server exposes the translators as a REST service.
+//
+// Usage:
+//
+//	translator-server -addr :8080 -config translator.yaml
+//
+// Endpoints:
+//
+//	POST /translate        {"text": "Hello", "from": "en", "to": "id", ...}  → gt.Translated
+//	POST /translate/batch  {"texts": ["Hello", "Bye"], "from": "en", "to": "id"}  → {"results": [...]}
+//	POST /detect           {"text": "Bonjour"}  → {"languages": [...]}
+//	GET  /languages        → {"languages": [...]}
+//
+// With -api libretranslate, the server speaks the LibreTranslate API instead:
+//
+//	POST /translate        {"q": "Hello", "source": "auto", "target": "id", "format": "text"}
+//	POST /detect           {"q": "Bonjour"}
+//	GET  /languages
+//
+// POST /translate accepts every field of params.Translate. Errors are returned
+// as {"error": {"kind": ..., "message": ...}} with a matching HTTP status, such
+// as 400 for invalid languages or 429 for rate limits. Settings are loaded from
+// the -config file and the TRANSLATOR_* environment variables, as described
+// in the config package. The server shuts down gracefully on SIGINT and SIGTERM.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	gt "gopkg.gilang.dev/translator/v2"
+	"gopkg.gilang.dev/translator/v2/config"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := run(ctx, os.Args[1:], os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "translator-server:", err)
+		os.Exit(1)
+	}
+}
+
+// run serves until ctx is done, then shuts the server down.
+func run(ctx context.Context, args []string, stderr io.Writer) error {
+	flags := flag.NewFlagSet("translator-server", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var (
+		addr            = flags.String("addr", ":8080", "listen address")
+		configPath      = flags.String("config", "", "JSON or YAML configuration file")
+		backend         = flags.String("backend", "", "translation backend (default from config, or google)")
+		timeout         = flags.Duration("timeout", 30*time.Second, "timeout of each request, 0 for none")
+		shutdownTimeout = flags.Duration("shutdown-timeout", 10*time.Second, "time given to in-flight requests on shutdown")
+		maxBody         = flags.Int64("max-body", 1<<20, "maximum request body size in bytes")
+		api             = flags.String("api", "native", "API to serve: native or libretranslate")
+	)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	c, err := config.Load(*configPath)
+	if err != nil {
+		return err
+	}
+	if *backend != "" {
+		c.Backend = *backend
+	}
+	if *api != "native" && *api != "libretranslate" {
+		return fmt.Errorf("unknown API %q", *api)
+	}
+	s, closer, err := newServer(c)
+	if err != nil {
+		return err
+	}
+	defer closer.Close()
+	s.timeout = *timeout
+	s.maxBody = *maxBody
+
+	handler := s.routes()
+	if *api == "libretranslate" {
+		handler = s.libreTranslateRoutes()
+	}
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+	if *timeout > 0 {
+		srv.ReadTimeout = *timeout
+		srv.WriteTimeout = *timeout + 5*time.Second
+	}
+
+	logger := slog.New(slog.NewTextHandler(stderr, nil))
+	errc := make(chan error, 1)
+	go func() {
+		logger.Info("listening", slog.String("addr", *addr), slog.String("backend", c.PrimaryBackend()), slog.String("api", *api))
+		errc <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Info("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// newServer creates a server translating with the stack described by c.
+// Detection and language lists use the primary backend of the stack directly,
+// sharing its client and rate limiter, since the stack only translates;
+// detection falls back to the offline detector.
+func newServer(c *config.Config) (*server, io.Closer, error) {
+	backends, err := c.BuildBackends()
+	if err != nil {
+		return nil, nil, err
+	}
+	translator, closer, err := c.BuildStack(backends)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s := &server{translator: translator, detector: gt.NewOfflineDetector()}
+	if detector, ok := backends[0].(gt.Detector); ok {
+		s.detector = detector
+	}
+	if lister, ok := backends[0].(gt.LanguageLister); ok {
+		s.lister = lister
+	}
+	return s, closer, nil
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	gt "gopkg.gilang.dev/translator/v2"
+)
+
+// server serves the REST API.
+type server struct {
+	translator gt.Translator
+	detector   gt.Detector
+	lister     gt.LanguageLister // nil if the backend can't list its languages
+	timeout    time.Duration     // Per request, none if zero
+	maxBody    int64
+}
+
+// batchRequest is the body of POST /translate/batch.
+type batchRequest struct {
+	Texts []string `json:"texts"`
+	From  string   `json:"from"`
+	To    string   `json:"to"`
+}
+
+// batchResult is a single result of POST /translate/batch.
+type batchResult struct {
+	Translated *gt.Translated `json:"translated,omitempty"`
+	Error      *errorBody     `json:"error,omitempty"`
+}
+
+// detectRequest is the body of POST /detect.
+type detectRequest struct {
+	Text string `json:"text"`
+}
+
+// errorBody describes an error in responses.
+type errorBody struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+	Backend string `json:"backend,omitempty"`
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /translate", s.handleTranslate)
+	mux.HandleFunc("POST /translate/batch", s.handleTranslateBatch)
+	mux.HandleFunc("POST /detect", s.handleDetect)
+	mux.HandleFunc("GET /languages", s.handleLanguages)
+	return mux
+}
+
+func (s *server) handleTranslate(w http.ResponseWriter, r *http.Request) {
+	var req gt.TranslateRequest
+	if !s.decode(w, r, &req) {
+		return
+	}
+	ctx, cancel := s.context(r)
+	defer cancel()
+
+	result, err := gt.DoWith(ctx, s.translator, req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *server) handleTranslateBatch(w http.ResponseWriter, r *http.Request) {
+	var req batchRequest
+	if !s.decode(w, r, &req) {
+		return
+	}
+	ctx, cancel := s.context(r)
+	defer cancel()
+
+	results, err := gt.TranslateBatchWith(ctx, s.translator, req.Texts, req.From, req.To)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	body := make([]batchResult, len(results))
+	for i, result := range results {
+		if result.Err != nil {
+			body[i].Error = newErrorBody(result.Err)
+			continue
+		}
+		body[i].Translated = result.Translated
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"results": body})
+}
+
+func (s *server) handleDetect(w http.ResponseWriter, r *http.Request) {
+	var req detectRequest
+	if !s.decode(w, r, &req) {
+		return
+	}
+	ctx, cancel := s.context(r)
+	defer cancel()
+
+	detected, err := gt.DetectWith(ctx, s.detector, req.Text)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"languages": detected})
+}
+
+func (s *server) handleLanguages(w http.ResponseWriter, r *http.Request) {
+	if s.lister == nil {
+		writeJSON(w, http.StatusNotImplemented, map[string]any{"error": errorBody{
+			Kind:    "not_implemented",
+			Message: "the backend can't list its languages",
+		}})
+		return
+	}
+	ctx, cancel := s.context(r)
+	defer cancel()
+
+	languages, err := gt.LanguagesWith(ctx, s.lister)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"languages": languages})
+}
+
+// context returns the context of a request, bounded by the server timeout.
+func (s *server) context(r *http.Request) (context.Context, context.CancelFunc) {
+	if s.timeout > 0 {
+		return context.WithTimeout(r.Context(), s.timeout)
+	}
+	return context.WithCancel(r.Context())
+}
+
+// decode decodes the JSON body of r into v, writing a 400 response on failure.
+func (s *server) decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	body := http.MaxBytesReader(w, r.Body, s.maxBody)
+	if err := json.NewDecoder(body).Decode(v); err != nil {
+		status := http.StatusBadRequest
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		writeJSON(w, status, map[string]any{"error": errorBody{Kind: "invalid_request", Message: err.Error()}})
+		return false
+	}
+	return true
+}
+
+// errorKinds maps error classes to their kind in responses and HTTP status.
+var errorKinds = []struct {
+	err    error
+	kind   string
+	status int
+}{
+	{gt.ErrEmptyText, "empty_text", http.StatusBadRequest},
+	{gt.ErrInvalidLanguage, "invalid_language", http.StatusBadRequest},
+	{gt.ErrUnsupportedPair, "unsupported_pair", http.StatusBadRequest},
+	{gt.ErrTextTooLong, "text_too_long", http.StatusRequestEntityTooLarge},
+	{gt.ErrRateLimited, "rate_limited", http.StatusTooManyRequests},
+	{gt.ErrCircuitOpen, "circuit_open", http.StatusServiceUnavailable},
+	{context.DeadlineExceeded, "timeout", http.StatusGatewayTimeout},
+	{gt.ErrNetwork, "network", http.StatusBadGateway},
+	{gt.ErrUnexpectedResponse, "unexpected_response", http.StatusBadGateway},
+}
+
+// classify returns the kind and HTTP status of err.
+func classify(err error) (string, int) {
+	for _, k := range errorKinds {
+		if errors.Is(err, k.err) {
+			return k.kind, k.status
+		}
+	}
+	return "internal", http.StatusInternalServerError
+}
+
+func newErrorBody(err error) *errorBody {
+	kind, _ := classify(err)
+	body := &errorBody{Kind: kind, Message: err.Error()}
+	var e *gt.Error
+	if errors.As(err, &e) {
+		body.Backend = e.Backend
+	}
+	return body
+}
+
+// writeError writes err with the status of its class.
+func writeError(w http.ResponseWriter, err error) {
+	_, status := classify(err)
+	setRetryAfter(w, err)
+	writeJSON(w, status, map[string]any{"error": newErrorBody(err)})
+}
+
+// setRetryAfter sets the Retry-After header when the backend asked to wait.
+func setRetryAfter(w http.ResponseWriter, err error) {
+	var e *gt.Error
+	if errors.As(err, &e) && e.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int((e.RetryAfter+time.Second-1)/time.Second)))
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(v)
+}
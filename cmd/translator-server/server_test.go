@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gt "gopkg.gilang.dev/translator/v2"
+	"gopkg.gilang.dev/translator/v2/config"
+	"gopkg.gilang.dev/translator/v2/errs"
+)
+
+const testBackend gt.TranslatorType = "server-test"
+
+func init() {
+	gt.Register(testBackend, func(gt.Config) (gt.Translator, error) {
+		return testTranslator{}, nil
+	})
+}
+
+// testTranslator upper-cases texts, or fails when the text is "rate".
+type testTranslator struct{}
+
+func (testTranslator) Translate(ctx context.Context, text, from, to string) (*gt.Translated, error) {
+	if text == "rate" {
+		e := errs.FromStatus(string(testBackend), http.StatusTooManyRequests, "")
+		e.RetryAfter = 1500 * time.Millisecond
+		return nil, e
+	}
+	req, _ := gt.RequestFromContext(ctx)
+	result := &gt.Translated{Text: strings.ToUpper(text), Backend: string(testBackend)}
+	result.From.Language.Iso = "en"
+	for i := 0; i < req.Alternatives; i++ {
+		result.Alternatives = append(result.Alternatives, strings.ToLower(text))
+	}
+	if req.Formality != "" {
+		result.Text += " (" + req.Formality + ")"
+	}
+	return result, nil
+}
+
+func (testTranslator) Detect(ctx context.Context, text string) ([]gt.DetectedLanguage, error) {
+	return []gt.DetectedLanguage{{Language: "fr", Confidence: 0.9}}, nil
+}
+
+func (testTranslator) Languages(ctx context.Context) ([]gt.Language, error) {
+	return []gt.Language{
+		{Code: "en", Name: "English", Source: true},
+		{Code: "EN-US", Name: "English (American)", Target: true},
+		{Code: "fr", Name: "French", Source: true, Target: true},
+	}, nil
+}
+
+func newTestServer(t *testing.T) *httptest.Server {
+	return newTestServerAPI(t, "native")
+}
+
+func newTestServerAPI(t *testing.T, api string) *httptest.Server {
+	s, closer, err := newServer(&config.Config{Backend: string(testBackend)})
+	require.NoError(t, err)
+	t.Cleanup(func() { closer.Close() })
+	s.timeout = time.Second
+	s.maxBody = 1 << 10
+	handler := s.routes()
+	if api == "libretranslate" {
+		handler = s.libreTranslateRoutes()
+	}
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func post(t *testing.T, ts *httptest.Server, path, body string) (*http.Response, map[string]any) {
+	resp, err := http.Post(ts.URL+path, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var decoded map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&decoded))
+	return resp, decoded
+}
+
+func TestTranslate(t *testing.T) {
+	ts := newTestServer(t)
+
+	resp, body := post(t, ts, "/translate", `{"text": "hello", "to": "de", "formality": "more"}`)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "HELLO (more)", body["text"])
+	assert.Equal(t, string(testBackend), body["backend"])
+
+	resp, body = post(t, ts, "/translate", `{"text": "hello", "to": "not a language"}`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "invalid_language", body["error"].(map[string]any)["kind"])
+
+	resp, body = post(t, ts, "/translate", `{"text": "rate", "to": "id"}`)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get("Retry-After"))
+	assert.Equal(t, string(testBackend), body["error"].(map[string]any)["backend"])
+
+	resp, _ = post(t, ts, "/translate", `{"text": `)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, _ = post(t, ts, "/translate", `{"text": "`+strings.Repeat("a", 2<<10)+`", "to": "id"}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+}
+
+func TestTranslateBatch(t *testing.T) {
+	ts := newTestServer(t)
+
+	resp, body := post(t, ts, "/translate/batch", `{"texts": ["hello", "rate"], "to": "id"}`)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	results := body["results"].([]any)
+	require.Len(t, results, 2)
+	assert.Equal(t, "HELLO", results[0].(map[string]any)["translated"].(map[string]any)["text"])
+	assert.Equal(t, "rate_limited", results[1].(map[string]any)["error"].(map[string]any)["kind"])
+
+	resp, body = post(t, ts, "/translate/batch", `{"texts": [], "to": "id"}`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "empty_text", body["error"].(map[string]any)["kind"])
+}
+
+func TestDetectAndLanguages(t *testing.T) {
+	ts := newTestServer(t)
+
+	resp, body := post(t, ts, "/detect", `{"text": "bonjour"}`)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []any{map[string]any{"language": "fr", "confidence": 0.9}}, body["languages"])
+
+	get, err := http.Get(ts.URL + "/languages")
+	require.NoError(t, err)
+	defer get.Body.Close()
+	data, _ := io.ReadAll(get.Body)
+	assert.Equal(t, http.StatusOK, get.StatusCode)
+	assert.JSONEq(t, `{"languages": [
+		{"code": "en", "name": "English", "source": true, "target": false},
+		{"code": "EN-US", "name": "English (American)", "source": false, "target": true},
+		{"code": "fr", "name": "French", "source": true, "target": true}
+	]}`, string(data))
+
+	get, err = http.Get(ts.URL + "/translate")
+	require.NoError(t, err)
+	get.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, get.StatusCode)
+}
+
+func TestRunShutdown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var stderr bytes.Buffer
+	done := make(chan error, 1)
+	go func() {
+		done <- run(ctx, []string{"-addr", "127.0.0.1:0", "-backend", string(testBackend)}, &stderr)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+	assert.Contains(t, stderr.String(), "shutting down")
+}
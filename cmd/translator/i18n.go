@@ -0,0 +1,145 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.gilang.dev/translator/v2/i18n"
+)
+
+// runI18n runs the i18n subcommand with args and returns its exit code.
+func runI18n(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("translator i18n", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: translator i18n -to <languages> [flags] <file>")
+		flags.PrintDefaults()
+	}
+	var (
+		to         = flags.String("to", "", "comma-separated target languages (required)")
+		from       = flags.String("from", "auto", "source language")
+		out        = flags.String("out", "", "target file path, where {lang} stands for the target language, or - for the standard output (default derived from the source file)")
+		backend    = flags.String("backend", "", "translation backend: "+backendNames()+" (default from config, or google)")
+		configPath = flags.String("config", "", "JSON or YAML configuration file")
+		timeout    = flags.Duration("timeout", 5*time.Minute, "timeout of each file translation, 0 for none")
+	)
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	targets := splitLanguages(*to)
+	if len(targets) == 0 || flags.NArg() != 1 {
+		fmt.Fprintln(stderr, "translator: -to and a single source file are required")
+		flags.Usage()
+		return exitUsage
+	}
+	source := flags.Arg(0)
+
+	paths := make([]string, len(targets))
+	for i, target := range targets {
+		paths[i] = targetPath(source, *out, *from, target)
+		if len(targets) > 1 && (paths[i] == "" || !strings.Contains(*out, "{lang}") && *out != "") {
+			fmt.Fprintln(stderr, "translator: -out with {lang} is required to write several languages")
+			return exitUsage
+		}
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	if source == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(source)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "translator:", err)
+		return exitError
+	}
+
+	translator, closer, err := newTranslator(*configPath, *backend)
+	if err != nil {
+		fmt.Fprintln(stderr, "translator:", err)
+		return exitUsage
+	}
+	defer closer.Close()
+	files := i18n.New(translator)
+
+	for i, target := range targets {
+		ctx, cancel := withTimeout(*timeout)
+		translated, err := files.Translate(ctx, data, *from, target)
+		cancel()
+		if err != nil {
+			fmt.Fprintf(stderr, "translator: %s: %v\n", target, err)
+			return exitCode(err)
+		}
+
+		if paths[i] == "" {
+			if _, err := stdout.Write(translated); err != nil {
+				fmt.Fprintln(stderr, "translator:", err)
+				return exitError
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(paths[i]), 0o755); err != nil {
+			fmt.Fprintln(stderr, "translator:", err)
+			return exitError
+		}
+		if err := os.WriteFile(paths[i], translated, 0o644); err != nil {
+			fmt.Fprintln(stderr, "translator:", err)
+			return exitError
+		}
+	}
+	return exitOK
+}
+
+// splitLanguages splits a comma-separated list of languages.
+func splitLanguages(list string) []string {
+	var languages []string
+	for _, language := range strings.Split(list, ",") {
+		if language = strings.TrimSpace(language); language != "" {
+			languages = append(languages, language)
+		}
+	}
+	return languages
+}
+
+// targetPath returns the path of the target file of source for language to:
+// out with {lang} replaced by to, "" for "-", or source with its base name or a
+// directory named from replaced by to, as in en.json or en/common.json. It
+// returns "" when the file goes to the standard output, as it does when the
+// path can't be derived.
+func targetPath(source, out, from, to string) string {
+	switch {
+	case out == "-":
+		return ""
+	case out != "":
+		return strings.ReplaceAll(out, "{lang}", to)
+	}
+	if source == "-" || from == "" || from == "auto" {
+		return ""
+	}
+
+	dir, base := filepath.Split(source)
+	ext := filepath.Ext(base)
+	if strings.EqualFold(strings.TrimSuffix(base, ext), from) {
+		return filepath.Join(dir, to+ext)
+	}
+	elements := strings.Split(filepath.ToSlash(filepath.Clean(dir)), "/")
+	for i := len(elements) - 1; i >= 0; i-- {
+		if strings.EqualFold(elements[i], from) {
+			elements[i] = to
+			return filepath.Join(filepath.FromSlash(strings.Join(elements, "/")), base)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runI18nTest(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(append([]string{"i18n", "-backend", string(testBackend)}, args...), strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRunI18n(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "locales", "en.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(source), 0o755))
+	require.NoError(t, os.WriteFile(source, []byte("{\n  \"hello\": \"Hello {{name}}\"\n}\n"), 0o644))
+
+	code, stdout, stderr := runI18nTest("", "-from", "en", "-to", "id, fr", source)
+	assert.Equal(t, exitOK, code, stderr)
+	assert.Empty(t, stdout)
+	for _, language := range []string{"id", "fr"} {
+		data, err := os.ReadFile(filepath.Join(dir, "locales", language+".json"))
+		require.NoError(t, err)
+		assert.Equal(t, "{\n  \"hello\": \"HELLO {{name}}\"\n}\n", string(data))
+	}
+
+	out := filepath.Join(dir, "out", "{lang}", "app.json")
+	code, _, stderr = runI18nTest("", "-to", "de", "-out", out, source)
+	assert.Equal(t, exitOK, code, stderr)
+	assert.FileExists(t, filepath.Join(dir, "out", "de", "app.json"))
+
+	code, stdout, _ = runI18nTest(`{"a":"b"}`, "-to", "id", "-")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, `{"a":"B"}`, stdout)
+}
+
+func TestRunI18nErrors(t *testing.T) {
+	tests := []struct {
+		args  []string
+		stdin string
+		code  int
+	}{
+		{[]string{"-"}, `{}`, exitUsage},
+		{[]string{"-to", "id"}, ``, exitUsage},
+		{[]string{"-to", "id,fr", "-"}, `{}`, exitUsage},
+		{[]string{"-to", "id,fr", "-out", "fixed.json", "-"}, `{}`, exitUsage},
+		{[]string{"-to", "id", "missing.json"}, ``, exitError},
+		{[]string{"-to", "id", "-"}, `{"a":`, exitError},
+		{[]string{"-to", "id", "-"}, `{"a":"rate"}`, exitRateLimited},
+	}
+	for _, tt := range tests {
+		code, stdout, stderr := runI18nTest(tt.stdin, tt.args...)
+		assert.Equal(t, tt.code, code, tt.args)
+		assert.Empty(t, stdout)
+		assert.NotEmpty(t, stderr)
+	}
+}
+
+func TestTargetPath(t *testing.T) {
+	tests := []struct{ source, out, from, want string }{
+		{"locales/en.json", "", "en", filepath.Join("locales", "id.json")},
+		{"locales/en/common.json", "", "en", filepath.Join("locales", "id", "common.json")},
+		{"/srv/app/en/common.json", "", "en", filepath.Join("/srv", "app", "id", "common.json")},
+		{"messages.json", "", "en", ""},
+		{"locales/en.json", "", "auto", ""},
+		{"en.json", "i18n/{lang}.json", "en", "i18n/id.json"},
+		{"en.json", "-", "en", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, targetPath(tt.source, tt.out, tt.from, "id"), tt.source)
+	}
+}
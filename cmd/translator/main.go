@@ -0,0 +1,197 @@
+// Command translator translates text from the command line.
+//
+// Usage:
+//
+//	translator -to id "Hello World"
+//	echo "Hello World" | translator -to id -from en -backend deepl
+//	translator -to ja -json "Good morning"
+//	translator i18n -from en -to id,fr locales/en.json
+//
+// The text is read from the arguments, or from standard input when there are
+// none or the only argument is "-". Settings are loaded from the -config file
+// and the TRANSLATOR_* environment variables, as described in the config
+// package; -backend overrides the configured backend.
+//
+// The i18n subcommand translates a JSON resource file, such as an i18next or
+// go-i18n file, into a file per target language, as described in the i18n
+// package. The target files are named after the -out template, where {lang}
+// stands for the target language, or after the source file, replacing the
+// file or directory name matching -from.
+//
+// The exit code tells the class of error:
+//
+//	0  success
+//	1  unexpected error, such as an unexpected backend response
+//	2  invalid usage, such as a missing -to flag or an empty text
+//	3  invalid language or unsupported language pair
+//	4  text too long for the backend
+//	5  rate limited by the backend
+//	6  network error, timeout or open circuit breaker
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	gt "gopkg.gilang.dev/translator/v2"
+	"gopkg.gilang.dev/translator/v2/config"
+)
+
+// Exit codes.
+const (
+	exitOK = iota
+	exitError
+	exitUsage
+	exitInvalidLanguage
+	exitTextTooLong
+	exitRateLimited
+	exitNetwork
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command with args and returns its exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) > 0 && args[0] == "i18n" {
+		return runI18n(args[1:], stdin, stdout, stderr)
+	}
+
+	flags := flag.NewFlagSet("translator", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: translator -to <language> [flags] [text...]")
+		fmt.Fprintln(stderr, "       translator i18n -to <languages> [flags] <file>")
+		flags.PrintDefaults()
+	}
+	var (
+		to           = flags.String("to", "", "target language (required)")
+		from         = flags.String("from", "auto", "source language")
+		backend      = flags.String("backend", "", "translation backend: "+backendNames()+" (default from config, or google)")
+		configPath   = flags.String("config", "", "JSON or YAML configuration file")
+		alternatives = flags.Int("alternatives", 0, "number of alternative translations to request")
+		jsonOutput   = flags.Bool("json", false, "print the full result as JSON")
+		timeout      = flags.Duration("timeout", 30*time.Second, "timeout of the translation, 0 for none")
+	)
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if *to == "" {
+		fmt.Fprintln(stderr, "translator: -to is required")
+		flags.Usage()
+		return exitUsage
+	}
+
+	text, err := readText(flags.Args(), stdin)
+	if err != nil {
+		fmt.Fprintln(stderr, "translator:", err)
+		return exitError
+	}
+
+	translator, closer, err := newTranslator(*configPath, *backend)
+	if err != nil {
+		fmt.Fprintln(stderr, "translator:", err)
+		return exitUsage
+	}
+	defer closer.Close()
+
+	ctx, cancel := withTimeout(*timeout)
+	defer cancel()
+
+	result, err := gt.DoWith(ctx, translator, gt.TranslateRequest{
+		Text:         text,
+		From:         *from,
+		To:           *to,
+		Alternatives: *alternatives,
+	})
+	if err != nil {
+		fmt.Fprintln(stderr, "translator:", err)
+		return exitCode(err)
+	}
+
+	if *jsonOutput {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(result); err != nil {
+			fmt.Fprintln(stderr, "translator:", err)
+			return exitError
+		}
+		return exitOK
+	}
+	fmt.Fprintln(stdout, result.Text)
+	return exitOK
+}
+
+// newTranslator builds the translator configured by the file at configPath,
+// if any, and the environment, using backend unless it is empty.
+func newTranslator(configPath, backend string) (gt.Translator, io.Closer, error) {
+	c, err := config.Load(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if backend != "" {
+		c.Backend = backend
+	}
+	return c.Build()
+}
+
+// withTimeout returns a context canceled after timeout, or never if timeout
+// is 0.
+func withTimeout(timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(context.Background(), timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// readText returns the text given in args, or read from stdin.
+func readText(args []string, stdin io.Reader) (string, error) {
+	if len(args) > 0 && !(len(args) == 1 && args[0] == "-") {
+		return strings.Join(args, " "), nil
+	}
+	data, err := io.ReadAll(stdin)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// exitCode returns the exit code for the class of err.
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, gt.ErrEmptyText):
+		return exitUsage
+	case errors.Is(err, gt.ErrInvalidLanguage), errors.Is(err, gt.ErrUnsupportedPair):
+		return exitInvalidLanguage
+	case errors.Is(err, gt.ErrTextTooLong):
+		return exitTextTooLong
+	case errors.Is(err, gt.ErrRateLimited):
+		return exitRateLimited
+	case errors.Is(err, gt.ErrNetwork), errors.Is(err, gt.ErrCircuitOpen),
+		errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return exitNetwork
+	}
+	return exitError
+}
+
+// backendNames returns the registered backends separated by "|".
+func backendNames() string {
+	names := gt.Registered()
+	s := make([]string, len(names))
+	for i, name := range names {
+		s[i] = string(name)
+	}
+	return strings.Join(s, "|")
+}
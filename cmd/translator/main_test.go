@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	gt "gopkg.gilang.dev/translator/v2"
+	"gopkg.gilang.dev/translator/v2/errs"
+)
+
+const testBackend gt.TranslatorType = "cli-test"
+
+func init() {
+	gt.Register(testBackend, func(gt.Config) (gt.Translator, error) {
+		return testTranslator{}, nil
+	})
+}
+
+// testTranslator upper-cases texts, or fails with the error named by the text.
+type testTranslator struct{}
+
+func (testTranslator) Translate(ctx context.Context, text, from, to string) (*gt.Translated, error) {
+	switch text {
+	case "rate":
+		return nil, errs.FromStatus("cli-test", 429, "")
+	case "long":
+		return nil, errs.FromStatus("cli-test", 413, "")
+	case "network":
+		return nil, errs.New(gt.ErrNetwork, "cli-test", 0, "", nil)
+	case "broken":
+		return nil, errs.FromStatus("cli-test", 500, "")
+	}
+	req, _ := gt.RequestFromContext(ctx)
+	result := &gt.Translated{Text: strings.ToUpper(text), Backend: string(testBackend)}
+	result.From.Language.Iso = "en"
+	for i := 0; i < req.Alternatives; i++ {
+		result.Alternatives = append(result.Alternatives, strings.ToLower(text))
+	}
+	return result, nil
+}
+
+func runTest(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(append([]string{"-backend", string(testBackend)}, args...), strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun(t *testing.T) {
+	code, stdout, _ := runTest("", "-to", "id", "hello", "world")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "HELLO WORLD\n", stdout)
+
+	code, stdout, _ = runTest("from stdin\n", "-to", "id")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "FROM STDIN\n", stdout)
+
+	code, stdout, _ = runTest("dash\n", "-to", "id", "-")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "DASH\n", stdout)
+}
+
+func TestRunJSON(t *testing.T) {
+	code, stdout, _ := runTest("", "-to", "id", "-json", "-alternatives", "1", "Hi")
+	assert.Equal(t, exitOK, code)
+
+	var result gt.Translated
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &result))
+	assert.Equal(t, "HI", result.Text)
+	assert.Equal(t, []string{"hi"}, result.Alternatives)
+	assert.Equal(t, "en", result.From.Language.Iso)
+}
+
+func TestRunExitCodes(t *testing.T) {
+	tests := []struct {
+		args []string
+		code int
+	}{
+		{[]string{"hello"}, exitUsage},
+		{[]string{"-to", "id", "-unknown", "hello"}, exitUsage},
+		{[]string{"-to", "id", "-backend", "unknown", "hello"}, exitUsage},
+		{[]string{"-to", "id"}, exitUsage},
+		{[]string{"-to", "not a language", "hello"}, exitInvalidLanguage},
+		{[]string{"-to", "id", "long"}, exitTextTooLong},
+		{[]string{"-to", "id", "rate"}, exitRateLimited},
+		{[]string{"-to", "id", "network"}, exitNetwork},
+		{[]string{"-to", "id", "broken"}, exitError},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			code, stdout, stderr := runTest("", tt.args...)
+			assert.Equal(t, tt.code, code)
+			assert.Empty(t, stdout)
+			assert.NotEmpty(t, stderr)
+		})
+	}
+}
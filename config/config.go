@@ -0,0 +1,305 @@
+// Package config builds translator stacks from JSON or YAML files, with
+// overrides from TRANSLATOR_* environment variables, so that deployments can
+// change backends and their settings without a rebuild.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gt "gopkg.gilang.dev/translator/v2"
+	"gopkg.gilang.dev/translator/v2/cache"
+	"gopkg.gilang.dev/translator/v2/ratelimit"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	DefaultBackend   = gt.Google
+	DefaultCacheSize = 1000
+)
+
+// Cache types.
+const (
+	CacheMemory = "memory"
+	CacheFile   = "file"
+)
+
+// Config describes a translator stack. The zero value builds a plain Google
+// translator.
+type Config struct {
+	Backend  string             `json:"backend,omitempty" yaml:"backend,omitempty"`   // Registered backend name, DefaultBackend if empty
+	Fallback []string           `json:"fallback,omitempty" yaml:"fallback,omitempty"` // Backends tried in order when Backend fails
+	Timeout  Duration           `json:"timeout,omitempty" yaml:"timeout,omitempty"`   // HTTP timeout of backends without their own
+	Backends map[string]Backend `json:"backends,omitempty" yaml:"backends,omitempty"` // Settings keyed by backend name
+	Retry    *Retry             `json:"retry,omitempty" yaml:"retry,omitempty"`
+	Cache    *Cache             `json:"cache,omitempty" yaml:"cache,omitempty"`
+}
+
+// Backend holds the settings of a single backend.
+type Backend struct {
+	Host      string            `json:"host,omitempty" yaml:"host,omitempty"`
+	Proxy     string            `json:"proxy,omitempty" yaml:"proxy,omitempty"`
+	DLSession string            `json:"dl_session,omitempty" yaml:"dl_session,omitempty"` // DeepL only
+	Timeout   Duration          `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	RateLimit *RateLimit        `json:"rate_limit,omitempty" yaml:"rate_limit,omitempty"`
+	Options   map[string]string `json:"options,omitempty" yaml:"options,omitempty"` // Passed to the backend factory
+}
+
+// Retry configures gt.NewRetryTranslator around every backend. Zero values
+// use the gt defaults.
+type Retry struct {
+	MaxAttempts int      `json:"max_attempts,omitempty" yaml:"max_attempts,omitempty"`
+	BaseDelay   Duration `json:"base_delay,omitempty" yaml:"base_delay,omitempty"`
+	MaxDelay    Duration `json:"max_delay,omitempty" yaml:"max_delay,omitempty"`
+}
+
+// Cache configures gt.NewCacheTranslator around the whole stack.
+type Cache struct {
+	Type string   `json:"type,omitempty" yaml:"type,omitempty"` // CacheMemory or CacheFile
+	Size int      `json:"size,omitempty" yaml:"size,omitempty"` // Memory only, DefaultCacheSize if zero
+	TTL  Duration `json:"ttl,omitempty" yaml:"ttl,omitempty"`   // Memory only, no expiry if zero
+	Path string   `json:"path,omitempty" yaml:"path,omitempty"` // File only
+}
+
+// RateLimit configures the ratelimit.Limiter of a backend.
+type RateLimit struct {
+	RequestsPerSecond float64 `json:"requests_per_second,omitempty" yaml:"requests_per_second,omitempty"`
+	Burst             int     `json:"burst,omitempty" yaml:"burst,omitempty"`
+	CharsPerMinute    int     `json:"chars_per_minute,omitempty" yaml:"chars_per_minute,omitempty"`
+}
+
+// Duration is a time.Duration written as a string such as "1.5s" or "300ms".
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Load reads the configuration file at path, if path isn't empty, and applies
+// the environment overrides. The format is chosen by the file extension:
+// .json, .yaml or .yml.
+func Load(path string) (*Config, error) {
+	c := &Config{}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+		if c, err = Parse(data, format); err != nil {
+			return nil, fmt.Errorf("config: %s: %w", path, err)
+		}
+	}
+	if err := c.ApplyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Parse decodes a configuration in format "json", "yaml" or "yml". Unknown
+// fields are rejected to catch typos.
+func Parse(data []byte, format string) (*Config, error) {
+	c := &Config{}
+	switch format {
+	case "json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(c); err != nil {
+			return nil, err
+		}
+	case "yaml", "yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("config: unsupported format %q", format)
+	}
+	return c, nil
+}
+
+// Build creates the translator stack described by c: every backend with its
+// client settings and rate limiter, each wrapped with retries, then fallback
+// in the configured order, then the cache. The returned closer releases the
+// cache file, if any, and must be called once the translator is no longer used.
+func (c *Config) Build() (gt.Translator, io.Closer, error) {
+	backends, err := c.BuildBackends()
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.BuildStack(backends)
+}
+
+// BuildBackends creates the primary backend followed by the fallback backends,
+// with BuildBackend. Together with BuildStack, it lets callers use the optional
+// interfaces of the backends that the stack translates with.
+func (c *Config) BuildBackends() ([]gt.Translator, error) {
+	names := c.stackNames()
+	backends := make([]gt.Translator, len(names))
+	for i, name := range names {
+		t, err := c.BuildBackend(name)
+		if err != nil {
+			return nil, err
+		}
+		backends[i] = t
+	}
+	return backends, nil
+}
+
+// BuildStack wraps backends, as returned by BuildBackends, with the retry,
+// fallback and cache layers of Build.
+func (c *Config) BuildStack(backends []gt.Translator) (gt.Translator, io.Closer, error) {
+	if len(backends) == 0 {
+		return nil, nil, errors.New("config: no backend")
+	}
+	translators := make([]gt.Translator, len(backends))
+	for i, t := range backends {
+		if c.Retry != nil {
+			t = gt.NewRetryTranslator(t, c.Retry.options()...)
+		}
+		translators[i] = t
+	}
+
+	translator := translators[0]
+	if len(translators) > 1 {
+		translator = gt.NewFallbackTranslator(translators[0], translators[1:]...)
+	}
+
+	var closer io.Closer = nopCloser{}
+	if c.Cache != nil {
+		store, storeCloser, err := c.Cache.store()
+		if err != nil {
+			return nil, nil, err
+		}
+		if store != nil {
+			// Keys name the configured backends, so that entries aren't
+			// served once the configuration changes to other backends.
+			backend := gt.WithCacheBackend(strings.Join(c.stackNames(), ","))
+			translator = gt.NewCacheTranslator(translator, store, backend)
+		}
+		if storeCloser != nil {
+			closer = storeCloser
+		}
+	}
+	return translator, closer, nil
+}
+
+// PrimaryBackend returns the name of the backend tried first.
+func (c *Config) PrimaryBackend() string {
+	if c.Backend == "" {
+		return string(DefaultBackend)
+	}
+	return c.Backend
+}
+
+// stackNames returns the name of the primary backend followed by the fallback
+// backends.
+func (c *Config) stackNames() []string {
+	return append([]string{c.PrimaryBackend()}, c.Fallback...)
+}
+
+// BuildBackend creates the translator of a single backend with gt.New, without
+// the retry, fallback and cache layers of Build. Unlike the stack, it keeps the
+// optional interfaces of the backend, such as gt.Detector.
+func (c *Config) BuildBackend(name string) (gt.Translator, error) {
+	b := c.Backends[name]
+	config := gt.Config{
+		Host:     b.Host,
+		ProxyURL: b.Proxy,
+		Timeout:  time.Duration(c.Timeout),
+	}
+	if b.Timeout > 0 {
+		config.Timeout = time.Duration(b.Timeout)
+	}
+	if b.RateLimit != nil {
+		config.RateLimiter = b.RateLimit.limiter()
+	}
+	if len(b.Options) > 0 || b.DLSession != "" {
+		config.Options = make(map[string]string, len(b.Options)+1)
+		for k, v := range b.Options {
+			config.Options[k] = v
+		}
+		if b.DLSession != "" {
+			config.Options["dl_session"] = b.DLSession
+		}
+	}
+	t, err := gt.New(gt.TranslatorType(name), config)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	return t, nil
+}
+
+func (r *Retry) options() []gt.RetryOption {
+	var opts []gt.RetryOption
+	if r.MaxAttempts > 0 {
+		opts = append(opts, gt.WithMaxAttempts(r.MaxAttempts))
+	}
+	if r.BaseDelay > 0 {
+		opts = append(opts, gt.WithBaseDelay(time.Duration(r.BaseDelay)))
+	}
+	if r.MaxDelay > 0 {
+		opts = append(opts, gt.WithMaxDelay(time.Duration(r.MaxDelay)))
+	}
+	return opts
+}
+
+// store creates the store described by c. An empty type disables caching.
+func (c *Cache) store() (gt.Store, io.Closer, error) {
+	switch c.Type {
+	case "":
+		return nil, nil, nil
+	case CacheMemory:
+		size := c.Size
+		if size == 0 {
+			size = DefaultCacheSize
+		}
+		return cache.NewLRU(size, time.Duration(c.TTL)), nil, nil
+	case CacheFile:
+		if c.Path == "" {
+			return nil, nil, errors.New("config: cache path is required for file cache")
+		}
+		store, err := cache.NewFile(c.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+		return store, store, nil
+	}
+	return nil, nil, fmt.Errorf("config: unknown cache type %q", c.Type)
+}
+
+func (r *RateLimit) limiter() *ratelimit.Limiter {
+	var opts []ratelimit.Option
+	if r.RequestsPerSecond > 0 {
+		burst := r.Burst
+		if burst < 1 {
+			burst = 1
+		}
+		opts = append(opts, ratelimit.WithRequestsPerSecond(r.RequestsPerSecond, burst))
+	}
+	if r.CharsPerMinute > 0 {
+		opts = append(opts, ratelimit.WithCharsPerMinute(r.CharsPerMinute))
+	}
+	return ratelimit.New(opts...)
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,250 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gt "gopkg.gilang.dev/translator/v2"
+)
+
+const yamlConfig = `
+backend: deepl
+fallback: [google]
+timeout: 10s
+backends:
+  deepl:
+    proxy: http://proxy:8080
+    dl_session: session
+    rate_limit:
+      requests_per_second: 2
+      burst: 4
+  google:
+    host: google.co.id
+retry:
+  max_attempts: 5
+  base_delay: 200ms
+cache:
+  type: memory
+  size: 100
+  ttl: 1h
+`
+
+const jsonConfig = `{
+  "backend": "deepl",
+  "fallback": ["google"],
+  "timeout": "10s",
+  "backends": {
+    "deepl": {"proxy": "http://proxy:8080", "dl_session": "session", "rate_limit": {"requests_per_second": 2, "burst": 4}},
+    "google": {"host": "google.co.id"}
+  },
+  "retry": {"max_attempts": 5, "base_delay": "200ms"},
+  "cache": {"type": "memory", "size": 100, "ttl": "1h"}
+}`
+
+func expectedConfig() *Config {
+	return &Config{
+		Backend:  "deepl",
+		Fallback: []string{"google"},
+		Timeout:  Duration(10 * time.Second),
+		Backends: map[string]Backend{
+			"deepl": {
+				Proxy:     "http://proxy:8080",
+				DLSession: "session",
+				RateLimit: &RateLimit{RequestsPerSecond: 2, Burst: 4},
+			},
+			"google": {Host: "google.co.id"},
+		},
+		Retry: &Retry{MaxAttempts: 5, BaseDelay: Duration(200 * time.Millisecond)},
+		Cache: &Cache{Type: CacheMemory, Size: 100, TTL: Duration(time.Hour)},
+	}
+}
+
+func TestParse(t *testing.T) {
+	c, err := Parse([]byte(yamlConfig), "yaml")
+	require.NoError(t, err)
+	assert.Equal(t, expectedConfig(), c)
+
+	c, err = Parse([]byte(jsonConfig), "json")
+	require.NoError(t, err)
+	assert.Equal(t, expectedConfig(), c)
+
+	_, err = Parse([]byte("backend: google\nbakend: deepl\n"), "yaml")
+	assert.Error(t, err)
+	_, err = Parse([]byte(`{"timeout": "soon"}`), "json")
+	assert.Error(t, err)
+	_, err = Parse(nil, "toml")
+	assert.Error(t, err)
+
+	c, err = Parse(nil, "yaml")
+	require.NoError(t, err)
+	assert.Equal(t, &Config{}, c)
+}
+
+func TestApplyEnv(t *testing.T) {
+	env := map[string]string{
+		"TRANSLATOR_BACKEND":                   "google",
+		"TRANSLATOR_FALLBACK":                  "deepl, ",
+		"TRANSLATOR_RETRY_MAX_DELAY":           "3s",
+		"TRANSLATOR_CACHE_TYPE":                "file",
+		"TRANSLATOR_CACHE_PATH":                "/tmp/cache.jsonl",
+		"TRANSLATOR_DEEPL_DL_SESSION":          "other",
+		"TRANSLATOR_GOOGLE_PROXY":              "http://proxy:3128",
+		"TRANSLATOR_GOOGLE_CHARS_PER_MINUTE":   "5000",
+		"TRANSLATOR_CUSTOM_BACKEND_HOST":       "ignored.example.com",
+		"TRANSLATOR_DEEPL_REQUESTS_PER_SECOND": "1.5",
+	}
+	lookup := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+
+	c := expectedConfig()
+	require.NoError(t, c.ApplyEnv(lookup))
+	assert.Equal(t, "google", c.Backend)
+	assert.Equal(t, []string{"deepl"}, c.Fallback)
+	assert.Equal(t, &Retry{MaxAttempts: 5, BaseDelay: Duration(200 * time.Millisecond), MaxDelay: Duration(3 * time.Second)}, c.Retry)
+	assert.Equal(t, &Cache{Type: CacheFile, Size: 100, TTL: Duration(time.Hour), Path: "/tmp/cache.jsonl"}, c.Cache)
+	assert.Equal(t, Backend{
+		Proxy:     "http://proxy:8080",
+		DLSession: "other",
+		RateLimit: &RateLimit{RequestsPerSecond: 1.5, Burst: 4},
+	}, c.Backends["deepl"])
+	assert.Equal(t, Backend{
+		Host:      "google.co.id",
+		Proxy:     "http://proxy:3128",
+		RateLimit: &RateLimit{CharsPerMinute: 5000},
+	}, c.Backends["google"])
+	assert.Len(t, c.Backends, 2)
+
+	c = &Config{}
+	env = map[string]string{"TRANSLATOR_RETRY_MAX_ATTEMPTS": "many", "TRANSLATOR_BACKEND": "deepl"}
+	err := c.ApplyEnv(lookup)
+	assert.ErrorContains(t, err, "TRANSLATOR_RETRY_MAX_ATTEMPTS")
+	assert.Equal(t, "deepl", c.Backend)
+	assert.Nil(t, c.Retry)
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "translator.yml")
+	require.NoError(t, os.WriteFile(path, []byte(yamlConfig), 0o644))
+	t.Setenv("TRANSLATOR_BACKEND", "google")
+
+	c, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "google", c.Backend)
+	assert.Equal(t, []string{"google"}, c.Fallback)
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestBuild(t *testing.T) {
+	const name gt.TranslatorType = "config-test"
+	var configs []gt.Config
+	gt.Register(name, func(config gt.Config) (gt.Translator, error) {
+		configs = append(configs, config)
+		return &countingTranslator{}, nil
+	})
+
+	c := &Config{
+		Backend:  string(name),
+		Timeout:  Duration(time.Second),
+		Backends: map[string]Backend{string(name): {Host: "example.com", DLSession: "s", Options: map[string]string{"key": "v"}}},
+		Retry:    &Retry{MaxAttempts: 2},
+		Cache:    &Cache{Type: CacheFile, Path: filepath.Join(t.TempDir(), "cache.jsonl")},
+	}
+	translator, closer, err := c.Build()
+	require.NoError(t, err)
+	defer closer.Close()
+
+	require.Len(t, configs, 1)
+	assert.Equal(t, "example.com", configs[0].Host)
+	assert.Equal(t, time.Second, configs[0].Timeout)
+	assert.Equal(t, map[string]string{"key": "v", "dl_session": "s"}, configs[0].Options)
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		result, err := translator.Translate(ctx, "hello", "en", "id")
+		require.NoError(t, err)
+		assert.Equal(t, "1", result.Text) // second call is served from the cache
+	}
+
+	_, _, err = (&Config{Backend: "unknown"}).Build()
+	assert.Error(t, err)
+	_, _, err = (&Config{Backend: string(name), Cache: &Cache{Type: "redis"}}).Build()
+	assert.Error(t, err)
+}
+
+func TestBuildStack(t *testing.T) {
+	const name gt.TranslatorType = "config-stack-test"
+	var built []*countingTranslator
+	gt.Register(name, func(config gt.Config) (gt.Translator, error) {
+		t := &countingTranslator{}
+		built = append(built, t)
+		return t, nil
+	})
+
+	c := &Config{Backend: string(name), Retry: &Retry{MaxAttempts: 2}}
+	backends, err := c.BuildBackends()
+	require.NoError(t, err)
+	translator, closer, err := c.BuildStack(backends)
+	require.NoError(t, err)
+	defer closer.Close()
+
+	_, err = translator.Translate(context.Background(), "hello", "en", "id")
+	require.NoError(t, err)
+	require.Len(t, built, 1) // the stack wraps the backend built by BuildBackends
+	assert.Same(t, built[0], backends[0])
+	assert.Equal(t, 1, built[0].calls)
+
+	_, _, err = c.BuildStack(nil)
+	assert.Error(t, err)
+}
+
+func TestBuildCacheBackend(t *testing.T) {
+	for _, name := range []gt.TranslatorType{"config-cache-a", "config-cache-b"} {
+		gt.Register(name, func(config gt.Config) (gt.Translator, error) {
+			return namedTranslator(name), nil
+		})
+	}
+	path := filepath.Join(t.TempDir(), "cache.jsonl")
+	translate := func(c *Config) string {
+		translator, closer, err := c.Build()
+		require.NoError(t, err)
+		defer closer.Close()
+		result, err := translator.Translate(context.Background(), "hello", "en", "id")
+		require.NoError(t, err)
+		return result.Text
+	}
+
+	cache := &Cache{Type: CacheFile, Path: path}
+	assert.Equal(t, "config-cache-a", translate(&Config{Backend: "config-cache-a", Cache: cache}))
+	assert.Equal(t, "config-cache-b", translate(&Config{Backend: "config-cache-b", Cache: cache}))
+	assert.Equal(t, "config-cache-a", translate(&Config{Backend: "config-cache-a", Cache: cache}))
+}
+
+func TestBuildDefault(t *testing.T) {
+	translator, closer, err := (&Config{Fallback: []string{"deepl"}}).Build()
+	require.NoError(t, err)
+	assert.NoError(t, closer.Close())
+	assert.NotNil(t, translator)
+}
+
+type countingTranslator struct{ calls int }
+
+func (c *countingTranslator) Translate(ctx context.Context, text, from, to string) (*gt.Translated, error) {
+	c.calls++
+	return &gt.Translated{Text: string(rune('0' + c.calls))}, nil
+}
+
+// namedTranslator translates every text to its own name.
+type namedTranslator string
+
+func (n namedTranslator) Translate(ctx context.Context, text, from, to string) (*gt.Translated, error) {
+	return &gt.Translated{Text: string(n)}, nil
+}
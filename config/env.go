@@ -0,0 +1,198 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	gt "gopkg.gilang.dev/translator/v2"
+)
+
+// EnvPrefix prefixes the environment variables read by ApplyEnv.
+const EnvPrefix = "TRANSLATOR_"
+
+// ApplyEnv overrides c with the environment variables returned by lookup,
+// usually os.LookupEnv:
+//
+//	TRANSLATOR_BACKEND                    backend
+//	TRANSLATOR_FALLBACK                   fallback, comma-separated
+//	TRANSLATOR_TIMEOUT                    timeout
+//	TRANSLATOR_RETRY_MAX_ATTEMPTS         retry.max_attempts
+//	TRANSLATOR_RETRY_BASE_DELAY           retry.base_delay
+//	TRANSLATOR_RETRY_MAX_DELAY            retry.max_delay
+//	TRANSLATOR_CACHE_TYPE                 cache.type
+//	TRANSLATOR_CACHE_SIZE                 cache.size
+//	TRANSLATOR_CACHE_TTL                  cache.ttl
+//	TRANSLATOR_CACHE_PATH                 cache.path
+//	TRANSLATOR_<NAME>_HOST                backends.<name>.host
+//	TRANSLATOR_<NAME>_PROXY               backends.<name>.proxy
+//	TRANSLATOR_<NAME>_DL_SESSION          backends.<name>.dl_session
+//	TRANSLATOR_<NAME>_TIMEOUT             backends.<name>.timeout
+//	TRANSLATOR_<NAME>_REQUESTS_PER_SECOND backends.<name>.rate_limit.requests_per_second
+//	TRANSLATOR_<NAME>_BURST               backends.<name>.rate_limit.burst
+//	TRANSLATOR_<NAME>_CHARS_PER_MINUTE    backends.<name>.rate_limit.chars_per_minute
+//
+// NAME is a registered or configured backend name in upper case, with dashes
+// replaced by underscores, such as GOOGLE or DEEPL.
+func (c *Config) ApplyEnv(lookup func(key string) (string, bool)) error {
+	e := envReader{lookup: lookup}
+
+	e.string("BACKEND", &c.Backend)
+	if v, ok := e.get("FALLBACK"); ok {
+		c.Fallback = splitList(v)
+	}
+	e.duration("TIMEOUT", &c.Timeout)
+
+	retry := c.Retry
+	if retry == nil {
+		retry = &Retry{}
+	}
+	changed := e.int("RETRY_MAX_ATTEMPTS", &retry.MaxAttempts)
+	changed = e.duration("RETRY_BASE_DELAY", &retry.BaseDelay) || changed
+	changed = e.duration("RETRY_MAX_DELAY", &retry.MaxDelay) || changed
+	if changed {
+		c.Retry = retry
+	}
+
+	cache := c.Cache
+	if cache == nil {
+		cache = &Cache{}
+	}
+	changed = e.string("CACHE_TYPE", &cache.Type)
+	changed = e.int("CACHE_SIZE", &cache.Size) || changed
+	changed = e.duration("CACHE_TTL", &cache.TTL) || changed
+	changed = e.string("CACHE_PATH", &cache.Path) || changed
+	if changed {
+		c.Cache = cache
+	}
+
+	for _, name := range c.backendNames() {
+		prefix := strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		b := c.Backends[name]
+		changed = e.string(prefix+"HOST", &b.Host)
+		changed = e.string(prefix+"PROXY", &b.Proxy) || changed
+		changed = e.string(prefix+"DL_SESSION", &b.DLSession) || changed
+		changed = e.duration(prefix+"TIMEOUT", &b.Timeout) || changed
+
+		rateLimit := b.RateLimit
+		if rateLimit == nil {
+			rateLimit = &RateLimit{}
+		}
+		limited := e.float(prefix+"REQUESTS_PER_SECOND", &rateLimit.RequestsPerSecond)
+		limited = e.int(prefix+"BURST", &rateLimit.Burst) || limited
+		limited = e.int(prefix+"CHARS_PER_MINUTE", &rateLimit.CharsPerMinute) || limited
+		if limited {
+			b.RateLimit = rateLimit
+			changed = true
+		}
+
+		if changed {
+			if c.Backends == nil {
+				c.Backends = make(map[string]Backend)
+			}
+			c.Backends[name] = b
+		}
+	}
+	return e.err
+}
+
+// backendNames returns the registered and configured backend names.
+func (c *Config) backendNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, name := range gt.Registered() {
+		add(string(name))
+	}
+	add(c.Backend)
+	for _, name := range c.Fallback {
+		add(name)
+	}
+	for name := range c.Backends {
+		add(name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// envReader reads prefixed environment variables, keeping the first error.
+// Its methods report whether the variable was set and valid.
+type envReader struct {
+	lookup func(string) (string, bool)
+	err    error
+}
+
+func (e *envReader) get(key string) (string, bool) {
+	return e.lookup(EnvPrefix + key)
+}
+
+func (e *envReader) fail(key string, err error) {
+	if e.err == nil {
+		e.err = fmt.Errorf("config: %s%s: %w", EnvPrefix, key, err)
+	}
+}
+
+func (e *envReader) string(key string, dst *string) bool {
+	v, ok := e.get(key)
+	if ok {
+		*dst = v
+	}
+	return ok
+}
+
+func (e *envReader) int(key string, dst *int) bool {
+	v, ok := e.get(key)
+	if !ok {
+		return false
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		e.fail(key, err)
+		return false
+	}
+	*dst = n
+	return true
+}
+
+func (e *envReader) float(key string, dst *float64) bool {
+	v, ok := e.get(key)
+	if !ok {
+		return false
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		e.fail(key, err)
+		return false
+	}
+	*dst = f
+	return true
+}
+
+func (e *envReader) duration(key string, dst *Duration) bool {
+	v, ok := e.get(key)
+	if !ok {
+		return false
+	}
+	if err := dst.UnmarshalText([]byte(v)); err != nil {
+		e.fail(key, err)
+		return false
+	}
+	return true
+}
+
+// splitList splits a comma-separated list, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
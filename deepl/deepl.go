@@ -2,12 +2,22 @@ package deepl
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"sort"
+	"strings"
 	"sync"
+	"unicode/utf8"
+
+	"github.com/abadojack/whatlanggo"
+	"gopkg.gilang.dev/translator/v2/errs"
+	"gopkg.gilang.dev/translator/v2/ratelimit"
 )
 
 const (
-	DefaultHost = "www2.deepl.com"
+	DefaultHost     = "www2.deepl.com"
+	BackendName     = "deepl"
+	DefaultMaxChars = 1500
 )
 
 // DeepL is a concurrency-safe client for the DeepL API.
@@ -17,6 +27,8 @@ type DeepL struct {
 	client    *http.Client
 	proxyURL  string
 	dlSession string
+	limiter   *ratelimit.Limiter
+	maxChars  int
 }
 
 // Option is a functional option for configuring DeepL.
@@ -50,11 +62,27 @@ func WithDLSession(dlSession string) Option {
 	}
 }
 
+// WithRateLimiter sets a limiter shared by all requests of the client.
+func WithRateLimiter(limiter *ratelimit.Limiter) Option {
+	return func(d *DeepL) {
+		d.limiter = limiter
+	}
+}
+
+// WithMaxChars sets the most characters sent in a single request. Longer
+// texts are split by the gt package. Zero disables splitting.
+func WithMaxChars(n int) Option {
+	return func(d *DeepL) {
+		d.maxChars = n
+	}
+}
+
 // New creates a new DeepL client with the given options.
 func New(opts ...Option) *DeepL {
 	d := &DeepL{
-		host:   DefaultHost,
-		client: &http.Client{},
+		host:     DefaultHost,
+		client:   &http.Client{},
+		maxChars: DefaultMaxChars,
 	}
 	for _, opt := range opts {
 		opt(d)
@@ -148,22 +176,136 @@ type Translated struct {
 
 // Translate translates text from one language to another.
 func (d *DeepL) Translate(ctx context.Context, text string, from string, to string) (*Translated, error) {
+	return d.TranslateWithOptions(ctx, text, from, to, TranslateOptions{})
+}
+
+// TranslateWithOptions translates text from one language to another with per-request options.
+func (d *DeepL) TranslateWithOptions(ctx context.Context, text string, from string, to string, opts TranslateOptions) (*Translated, error) {
+	d.mu.RLock()
+	client := d.client
+	proxyURL := d.proxyURL
+	dlSession := d.dlSession
+	limiter := d.limiter
+	d.mu.RUnlock()
+
+	results, err := translateGroups(ctx, limiter, client, from, to, []string{text}, opts, proxyURL, dlSession)
+	if err != nil {
+		return nil, err
+	}
+	return toTranslated(results[0])
+}
+
+// BatchResult holds the outcome for a single text of a batch translation.
+type BatchResult struct {
+	Translated *Translated
+	Err        error
+}
+
+// TranslateBatch translates several texts from one language to another using a
+// single request, or one per detected language as TranslateTextsByDeepL
+// does, each waiting for the rate limiter. Results are returned in the order
+// of texts, each carrying either a translation or its own error.
+func (d *DeepL) TranslateBatch(ctx context.Context, texts []string, from string, to string) ([]BatchResult, error) {
+	return d.TranslateBatchWithOptions(ctx, texts, from, to, TranslateOptions{})
+}
+
+// TranslateBatchWithOptions is like TranslateBatch with per-request options.
+func (d *DeepL) TranslateBatchWithOptions(ctx context.Context, texts []string, from string, to string, opts TranslateOptions) ([]BatchResult, error) {
 	d.mu.RLock()
 	client := d.client
 	proxyURL := d.proxyURL
 	dlSession := d.dlSession
+	limiter := d.limiter
 	d.mu.RUnlock()
 
-	result, err := TranslateByDeepL(ctx, client, from, to, text, "", proxyURL, dlSession)
+	results, err := translateGroups(ctx, limiter, client, from, to, texts, opts, proxyURL, dlSession)
 	if err != nil {
 		return nil, err
 	}
 
+	batch := make([]BatchResult, len(results))
+	for i, result := range results {
+		batch[i].Translated, batch[i].Err = toTranslated(result)
+	}
+	return batch, nil
+}
+
+// DetectedLanguage is a candidate source language with a confidence between 0 and 1.
+type DetectedLanguage struct {
+	Language   string  `json:"language"`
+	Confidence float64 `json:"confidence"`
+}
+
+// Detect returns the candidate languages of text as detected by DeepL, most
+// likely first. When DeepL reports no scores, its detected language is
+// returned alone with a confidence of 1.
+//
+// DeepL detects the language while translating, and rejects translations into
+// the source language, so text is translated into English, or into German
+// when it looks English. The other target is tried if the first one fails.
+func (d *DeepL) Detect(ctx context.Context, text string) ([]DetectedLanguage, error) {
+	d.mu.RLock()
+	client := d.client
+	proxyURL := d.proxyURL
+	dlSession := d.dlSession
+	limiter := d.limiter
+	d.mu.RUnlock()
+
+	var result DeepLTranslationResult
+	for n, target := range detectTargets(text) {
+		if err := limiter.Wait(ctx, utf8.RuneCountInString(text)); err != nil {
+			return nil, err
+		}
+		results, err := translateTexts(ctx, client, "auto", target, []string{text}, TranslateOptions{Alternatives: -1}, proxyURL, dlSession)
+		if err != nil {
+			return nil, err
+		}
+		result = results[0]
+		if _, err := toTranslated(result); err != nil {
+			if n > 0 || ctx.Err() != nil || errors.Is(err, errs.ErrRateLimited) {
+				return nil, err
+			}
+			continue
+		}
+		break
+	}
+
+	if len(result.Detected) > 0 {
+		detected := make([]DetectedLanguage, 0, len(result.Detected))
+		for lang, confidence := range result.Detected {
+			detected = append(detected, DetectedLanguage{Language: lang, Confidence: confidence})
+		}
+		sort.Slice(detected, func(i, j int) bool {
+			if detected[i].Confidence != detected[j].Confidence {
+				return detected[i].Confidence > detected[j].Confidence
+			}
+			return detected[i].Language < detected[j].Language
+		})
+		return detected, nil
+	}
+
+	if result.SourceLang == "" || strings.EqualFold(result.SourceLang, "auto") {
+		return nil, errs.New(errs.ErrUnexpectedResponse, BackendName, http.StatusOK, "no detected language in response", nil)
+	}
+	return []DetectedLanguage{{Language: result.SourceLang, Confidence: 1}}, nil
+}
+
+// detectTargets returns the targets of the translations made by Detect, in
+// order: English, or German first when text looks English.
+func detectTargets(text string) []string {
+	if whatlanggo.DetectLang(text) == whatlanggo.Eng {
+		return []string{"DE", "EN"}
+	}
+	return []string{"EN", "DE"}
+}
+
+// toTranslated converts a raw DeepL result into a Translated value.
+func toTranslated(result DeepLTranslationResult) (*Translated, error) {
 	if result.Code != http.StatusOK {
-		return nil, &TranslationError{
-			Code:    result.Code,
-			Message: result.Message,
+		if result.Err != nil {
+			return nil, result.Err
 		}
+		return nil, errs.FromStatus(BackendName, result.Code, result.Message)
 	}
 
 	return &Translated{
@@ -179,6 +321,8 @@ func (d *DeepL) Translate(ctx context.Context, text string, from string, to stri
 }
 
 // TranslationError represents a translation error.
+//
+// Deprecated: Translate and TranslateBatch report failures as *errs.Error.
 type TranslationError struct {
 	Code    int
 	Message string
@@ -190,3 +334,31 @@ func (e *TranslationError) Error() string {
 	}
 	return "translation failed"
 }
+
+// RateLimiter returns the current rate limiter.
+func (d *DeepL) RateLimiter() *ratelimit.Limiter {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.limiter
+}
+
+// SetRateLimiter sets the rate limiter.
+func (d *DeepL) SetRateLimiter(limiter *ratelimit.Limiter) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.limiter = limiter
+}
+
+// MaxChars returns the most characters sent in a single request.
+func (d *DeepL) MaxChars() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.maxChars
+}
+
+// SetMaxChars sets the most characters sent in a single request.
+func (d *DeepL) SetMaxChars(n int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.maxChars = n
+}
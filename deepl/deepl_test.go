@@ -2,9 +2,15 @@ package deepl
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"reflect"
 	"testing"
 	"time"
+
+	"github.com/tidwall/gjson"
+	"gopkg.gilang.dev/translator/v2/errs"
+	"gopkg.gilang.dev/translator/v2/ratelimit"
 )
 
 func TestNew(t *testing.T) {
@@ -87,3 +93,117 @@ func TestTranslate(t *testing.T) {
 		t.Error("Translation returned empty text")
 	}
 }
+
+func TestRPCError(t *testing.T) {
+	tests := []struct {
+		body string
+		kind error
+	}{
+		{`{"error":{"code":1042912,"message":"Too many requests"}}`, errs.ErrRateLimited},
+		{`{"error":{"code":-32600,"message":"Value for 'target_lang' not supported."}}`, errs.ErrUnsupportedPair},
+		{`{"error":{"code":-32600,"message":"Invalid request"}}`, errs.ErrUnexpectedResponse},
+	}
+	for _, tt := range tests {
+		err := rpcError(gjson.Get(tt.body, "error"))
+		if !errors.Is(err, tt.kind) {
+			t.Errorf("%s: expected %v, got %v", tt.body, tt.kind, err)
+		}
+		if err.Backend != BackendName {
+			t.Errorf("Expected backend %s, got %s", BackendName, err.Backend)
+		}
+	}
+}
+
+func TestTranslateEmptyText(t *testing.T) {
+	d := New()
+	_, err := d.Translate(context.Background(), "", "en", "id")
+	if !errors.Is(err, errs.ErrEmptyText) {
+		t.Errorf("Expected ErrEmptyText, got %v", err)
+	}
+}
+
+func TestWithRateLimiter(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.WithRequestsPerSecond(1, 1))
+	d := New(WithRateLimiter(limiter))
+	if d.RateLimiter() != limiter {
+		t.Error("Rate limiter not set correctly")
+	}
+	d.SetRateLimiter(nil)
+	if d.RateLimiter() != nil {
+		t.Error("Rate limiter not cleared")
+	}
+}
+
+func TestCommonJobParams(t *testing.T) {
+	if commonJobParams(TranslateOptions{}) != nil {
+		t.Error("Expected no job params without options")
+	}
+	p := commonJobParams(TranslateOptions{Formality: "more", TagHandling: "html", RegionalVariant: "en-GB"})
+	if p == nil || p.Formality != "formal" || p.TextType != "richtext" || p.RegionalVariant != "en-GB" {
+		t.Errorf("Unexpected job params %+v", p)
+	}
+	if p := commonJobParams(TranslateOptions{Formality: "less"}); p == nil || p.Formality != "informal" {
+		t.Errorf("Unexpected job params %+v", p)
+	}
+	if p := commonJobParams(TranslateOptions{Context: "A button label"}); p == nil || p.Context != "A button label" {
+		t.Errorf("Unexpected job params %+v", p)
+	}
+}
+
+func TestParseDetectedLanguages(t *testing.T) {
+	detected := parseDetectedLanguages(gjson.Parse(`{"EN":0.9,"de":0.05,"unsupported":"x"}`))
+	if len(detected) != 2 || detected["EN"] != 0.9 || detected["DE"] != 0.05 {
+		t.Errorf("Unexpected detected languages %v", detected)
+	}
+	if parseDetectedLanguages(gjson.Parse(`"EN"`)) != nil {
+		t.Error("Expected nil for a non-object value")
+	}
+}
+
+func TestSplitVariant(t *testing.T) {
+	tests := map[string][2]string{
+		"EN-GB":   {"EN", "en-GB"},
+		"ZH-HANS": {"ZH", "zh-Hans"},
+		"ES-419":  {"ES", "es-419"},
+		"DE":      {"DE", ""},
+	}
+	for target, want := range tests {
+		base, variant := splitVariant(target)
+		if base != want[0] || variant != want[1] {
+			t.Errorf("%s: expected %v, got %s %s", target, want, base, variant)
+		}
+	}
+}
+
+func TestGroupByLanguage(t *testing.T) {
+	texts := []string{
+		"The weather is lovely today and we are going to the beach.",
+		"Save",
+		"Das Wetter ist heute schön und wir gehen an den Strand.",
+		"I would like a cup of coffee with milk, please.",
+		"Cancel",
+	}
+	languages, groups := GroupByLanguage(texts)
+	if !reflect.DeepEqual(languages, []string{"EN", "auto", "DE"}) {
+		t.Errorf("Unexpected languages %v", languages)
+	}
+	if !reflect.DeepEqual(groups, [][]int{{0, 3}, {1, 4}, {2}}) {
+		t.Errorf("Unexpected groups %v", groups)
+	}
+
+	// UI strings aren't reliably detected, and are sent together
+	languages, groups = GroupByLanguage([]string{"Save", "Cancel", "Open file"})
+	if !reflect.DeepEqual(languages, []string{"auto"}) || !reflect.DeepEqual(groups, [][]int{{0, 1, 2}}) {
+		t.Errorf("Unexpected languages %v and groups %v", languages, groups)
+	}
+}
+
+func TestDetectTargets(t *testing.T) {
+	// English text isn't translated into English, which DeepL rejects
+	if targets := detectTargets("Where is the nearest train station?"); !reflect.DeepEqual(targets, []string{"DE", "EN"}) {
+		t.Errorf("Unexpected targets for English text %v", targets)
+	}
+	if targets := detectTargets("Bonjour, comment allez-vous aujourd'hui ?"); !reflect.DeepEqual(targets, []string{"EN", "DE"}) {
+		t.Errorf("Unexpected targets for French text %v", targets)
+	}
+}
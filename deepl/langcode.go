@@ -0,0 +1,107 @@
+package deepl
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/language"
+	"gopkg.gilang.dev/translator/v2/errs"
+)
+
+var (
+	// supported holds the codes of languages.
+	supported = func() map[string]bool {
+		codes := make(map[string]bool, len(languages))
+		for _, lang := range languages {
+			codes[lang.Code] = true
+		}
+		return codes
+	}()
+
+	// americanEnglish holds the regions defaulting to American English.
+	americanEnglish = map[string]bool{"US": true, "CA": true, "PH": true, "PR": true, "AS": true, "GU": true, "MP": true, "UM": true, "VI": true}
+
+	latinAmerica = language.MustParseRegion("419")
+)
+
+// SourceLanguageCode converts a BCP 47 language tag into the source language
+// code DeepL expects, such as "EN" for "en-GB" or "NB" for "no". "auto" is
+// returned unchanged. Languages DeepL doesn't support are reported with
+// errs.ErrUnsupportedPair.
+func SourceLanguageCode(tag string) (string, error) {
+	if tag == "auto" {
+		return tag, nil
+	}
+	_, code, err := parseLanguage(tag)
+	return code, err
+}
+
+// TargetLanguageCode converts a BCP 47 language tag into the target language
+// code DeepL expects, picking a regional variant where DeepL requires one:
+// "EN-US" or "EN-GB" for English, "PT-BR" or "PT-PT" for Portuguese and
+// "ZH-HANS" or "ZH-HANT" for Chinese. Latin American Spanish becomes "ES-419".
+func TargetLanguageCode(tag string) (string, error) {
+	parsed, code, err := parseLanguage(tag)
+	if err != nil {
+		return "", err
+	}
+
+	region, confidence := parsed.Region()
+	exact := confidence == language.Exact
+	switch code {
+	case "EN":
+		if exact && !americanEnglish[region.String()] {
+			return "EN-GB", nil
+		}
+		return "EN-US", nil
+	case "PT":
+		if exact && region.String() != "BR" {
+			return "PT-PT", nil
+		}
+		return "PT-BR", nil
+	case "ZH":
+		if script, _ := parsed.Script(); script.String() == "Hant" {
+			return "ZH-HANT", nil
+		}
+		return "ZH-HANS", nil
+	case "ES":
+		if exact && latinAmerica.Contains(region) {
+			return "ES-419", nil
+		}
+	}
+	return code, nil
+}
+
+// parseLanguage parses tag and returns its DeepL base language code.
+func parseLanguage(tag string) (language.Tag, string, error) {
+	parsed, err := language.Parse(tag)
+	if err != nil {
+		return parsed, "", errs.New(errs.ErrInvalidLanguage, BackendName, 0, fmt.Sprintf("language %q isn't valid", tag), err)
+	}
+
+	base, _ := parsed.Base()
+	code := strings.ToUpper(base.String())
+	if code == "NO" || code == "NN" {
+		code = "NB"
+	}
+	if !supported[code] {
+		return parsed, "", errs.New(errs.ErrUnsupportedPair, BackendName, 0, fmt.Sprintf("language %q isn't supported", tag), nil)
+	}
+	return parsed, code, nil
+}
+
+// splitVariant splits a target code such as "EN-GB" into the base code DeepL
+// expects as target language and the matching regional variant, "en-GB".
+func splitVariant(targetLang string) (string, string) {
+	base, suffix, ok := strings.Cut(targetLang, "-")
+	if !ok {
+		return targetLang, ""
+	}
+	if len(suffix) == 4 {
+		// Script subtag such as Hans
+		suffix = strings.ToUpper(suffix[:1]) + strings.ToLower(suffix[1:])
+	} else {
+		suffix = strings.ToUpper(suffix)
+	}
+	return strings.ToUpper(base), strings.ToLower(base) + "-" + suffix
+}
@@ -0,0 +1,61 @@
+package deepl
+
+import "context"
+
+// Language describes a language supported by DeepL.
+type Language struct {
+	Code              string   `json:"code"`
+	Name              string   `json:"name"`
+	Source            bool     `json:"source"`
+	Target            bool     `json:"target"`
+	SupportsFormality bool     `json:"supports_formality"`
+	Variants          []string `json:"variants,omitempty"` // Regional variants accepted as target
+}
+
+// languages lists the languages of the DeepL web translator.
+var languages = []Language{
+	{Code: "AR", Name: "Arabic", Source: true, Target: true},
+	{Code: "BG", Name: "Bulgarian", Source: true, Target: true},
+	{Code: "CS", Name: "Czech", Source: true, Target: true},
+	{Code: "DA", Name: "Danish", Source: true, Target: true},
+	{Code: "DE", Name: "German", Source: true, Target: true, SupportsFormality: true},
+	{Code: "EL", Name: "Greek", Source: true, Target: true},
+	{Code: "EN", Name: "English", Source: true, Target: true, Variants: []string{"EN-GB", "EN-US"}},
+	{Code: "ES", Name: "Spanish", Source: true, Target: true, SupportsFormality: true, Variants: []string{"ES-419"}},
+	{Code: "ET", Name: "Estonian", Source: true, Target: true},
+	{Code: "FI", Name: "Finnish", Source: true, Target: true},
+	{Code: "FR", Name: "French", Source: true, Target: true, SupportsFormality: true},
+	{Code: "HE", Name: "Hebrew", Source: true, Target: true},
+	{Code: "HU", Name: "Hungarian", Source: true, Target: true},
+	{Code: "ID", Name: "Indonesian", Source: true, Target: true},
+	{Code: "IT", Name: "Italian", Source: true, Target: true, SupportsFormality: true},
+	{Code: "JA", Name: "Japanese", Source: true, Target: true, SupportsFormality: true},
+	{Code: "KO", Name: "Korean", Source: true, Target: true},
+	{Code: "LT", Name: "Lithuanian", Source: true, Target: true},
+	{Code: "LV", Name: "Latvian", Source: true, Target: true},
+	{Code: "NB", Name: "Norwegian (Bokmål)", Source: true, Target: true},
+	{Code: "NL", Name: "Dutch", Source: true, Target: true, SupportsFormality: true},
+	{Code: "PL", Name: "Polish", Source: true, Target: true, SupportsFormality: true},
+	{Code: "PT", Name: "Portuguese", Source: true, Target: true, SupportsFormality: true, Variants: []string{"PT-BR", "PT-PT"}},
+	{Code: "RO", Name: "Romanian", Source: true, Target: true},
+	{Code: "RU", Name: "Russian", Source: true, Target: true, SupportsFormality: true},
+	{Code: "SK", Name: "Slovak", Source: true, Target: true},
+	{Code: "SL", Name: "Slovenian", Source: true, Target: true},
+	{Code: "SV", Name: "Swedish", Source: true, Target: true},
+	{Code: "TH", Name: "Thai", Source: true, Target: true},
+	{Code: "TR", Name: "Turkish", Source: true, Target: true},
+	{Code: "UK", Name: "Ukrainian", Source: true, Target: true},
+	{Code: "VI", Name: "Vietnamese", Source: true, Target: true},
+	{Code: "ZH", Name: "Chinese", Source: true, Target: true, Variants: []string{"ZH-HANS", "ZH-HANT"}},
+}
+
+// Languages returns the languages DeepL accepts, with the formality support
+// and regional variants of each target language.
+func (d *DeepL) Languages(ctx context.Context) ([]Language, error) {
+	result := make([]Language, len(languages))
+	for i, lang := range languages {
+		lang.Variants = append([]string(nil), lang.Variants...)
+		result[i] = lang
+	}
+	return result, nil
+}
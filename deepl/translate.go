@@ -5,17 +5,20 @@ import (
 	"compress/flate"
 	"compress/gzip"
 	"context"
-	"fmt"
+	"errors"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/abadojack/whatlanggo"
 	"github.com/imroc/req/v3"
 
 	"github.com/andybalholm/brotli"
 	"github.com/tidwall/gjson"
+	"gopkg.gilang.dev/translator/v2/errs"
+	"gopkg.gilang.dev/translator/v2/ratelimit"
 )
 
 // makeRequestWithBody makes an HTTP request with pre-formatted body using minimal headers.
@@ -69,7 +72,7 @@ func makeRequestWithBody(ctx context.Context, httpClient *http.Client, postStr s
 	if proxyURL != "" {
 		proxy, err := url.Parse(proxyURL)
 		if err != nil {
-			return gjson.Result{}, err
+			return gjson.Result{}, errs.New(errs.ErrNetwork, BackendName, 0, "invalid proxy url", err)
 		}
 		client.SetProxyURL(proxy.String())
 	}
@@ -83,17 +86,19 @@ func makeRequestWithBody(ctx context.Context, httpClient *http.Client, postStr s
 		Post(urlFull)
 
 	if err != nil {
-		return gjson.Result{}, err
+		return gjson.Result{}, errs.New(errs.ErrNetwork, BackendName, 0, "bad network", err)
 	}
 
 	// Check for blocked status like TypeScript version
 	if resp.StatusCode == 429 {
-		return gjson.Result{}, fmt.Errorf("too many requests, your IP has been blocked by DeepL temporarily, please don't request it frequently in a short time")
+		e := errs.FromStatus(BackendName, resp.StatusCode, "too many requests, your IP has been blocked by DeepL temporarily, please don't request it frequently in a short time")
+		e.RetryAfter = errs.ParseRetryAfter(resp.Header.Get("Retry-After"))
+		return gjson.Result{}, e
 	}
 
 	// Check for other error status codes
 	if resp.StatusCode != 200 {
-		return gjson.Result{}, fmt.Errorf("request failed with status code: %d", resp.StatusCode)
+		return gjson.Result{}, errs.FromStatus(BackendName, resp.StatusCode, "")
 	}
 
 	var bodyReader io.Reader
@@ -104,7 +109,7 @@ func makeRequestWithBody(ctx context.Context, httpClient *http.Client, postStr s
 	case "gzip":
 		bodyReader, err = gzip.NewReader(resp.Body)
 		if err != nil {
-			return gjson.Result{}, fmt.Errorf("failed to create gzip reader: %w", err)
+			return gjson.Result{}, errs.New(errs.ErrUnexpectedResponse, BackendName, resp.StatusCode, "failed to create gzip reader", err)
 		}
 	case "deflate":
 		bodyReader = flate.NewReader(resp.Body)
@@ -114,7 +119,7 @@ func makeRequestWithBody(ctx context.Context, httpClient *http.Client, postStr s
 
 	body, err := io.ReadAll(bodyReader)
 	if err != nil {
-		return gjson.Result{}, fmt.Errorf("failed to read response body: %w", err)
+		return gjson.Result{}, errs.New(errs.ErrNetwork, BackendName, resp.StatusCode, "failed to read response body", err)
 	}
 	return gjson.ParseBytes(body), nil
 }
@@ -126,21 +131,142 @@ func makeRequestWithBody(ctx context.Context, httpClient *http.Client, postStr s
 // text is the content to translate, tagHandling controls how markup is treated, proxyURL
 // optionally configures an outbound proxy, and dlSession carries the DeepL session token.
 func TranslateByDeepL(ctx context.Context, httpClient *http.Client, sourceLang, targetLang, text string, tagHandling string, proxyURL string, dlSession string) (DeepLTranslationResult, error) {
-	if text == "" {
-		return DeepLTranslationResult{
-			Code:    http.StatusNotFound,
-			Message: "No text to translate",
-		}, nil
+	results, err := TranslateTextsByDeepL(ctx, httpClient, sourceLang, targetLang, []string{text}, TranslateOptions{TagHandling: tagHandling}, proxyURL, dlSession)
+	if err != nil {
+		return DeepLTranslationResult{}, err
 	}
+	return results[0], nil
+}
+
+// TranslateTextsByDeepL performs translation of several texts using a single
+// LMT_handle_texts request, or one per language when an auto-detected source
+// differs between texts, texts not reliably detected being sent with "auto". The parameters mirror TranslateByDeepL, with opts
+// carrying tag handling and the other per-request settings. One result is
+// returned per input text, in input order; empty texts are not sent and are reported
+// with http.StatusNotFound, and request-level failures are reported on every item.
+func TranslateTextsByDeepL(ctx context.Context, httpClient *http.Client, sourceLang, targetLang string, texts []string, opts TranslateOptions, proxyURL string, dlSession string) ([]DeepLTranslationResult, error) {
+	return translateGroups(ctx, nil, httpClient, sourceLang, targetLang, texts, opts, proxyURL, dlSession)
+}
 
-	// Get detected language if source language is auto
+// translateGroups implements TranslateTextsByDeepL, waiting for limiter before
+// each request it sends.
+func translateGroups(ctx context.Context, limiter *ratelimit.Limiter, httpClient *http.Client, sourceLang, targetLang string, texts []string, opts TranslateOptions, proxyURL string, dlSession string) ([]DeepLTranslationResult, error) {
+	var (
+		languages = []string{sourceLang}
+		groups    = [][]int{make([]int, len(texts))}
+	)
+	for i := range texts {
+		groups[0][i] = i
+	}
 	if sourceLang == "auto" || sourceLang == "" {
-		sourceLang = strings.ToUpper(whatlanggo.DetectLang(text).Iso6391())
+		// Send the texts of each reliably detected language together, and
+		// let DeepL detect the language of the others
+		languages, groups = GroupByLanguage(texts)
+	}
+	results := make([]DeepLTranslationResult, len(texts))
+	for n, indexes := range groups {
+		group := make([]string, len(indexes))
+		for j, i := range indexes {
+			group[j] = texts[i]
+		}
+		chars := 0
+		for _, text := range group {
+			chars += utf8.RuneCountInString(text)
+		}
+		if chars > 0 { // Otherwise no request is sent
+			if err := limiter.Wait(ctx, chars); err != nil {
+				return nil, err
+			}
+		}
+		groupResults, err := translateTexts(ctx, httpClient, languages[n], targetLang, group, opts, proxyURL, dlSession)
+		if err != nil {
+			return nil, err
+		}
+		for j, i := range indexes {
+			results[i] = groupResults[j]
+		}
+	}
+	return results, nil
+}
+
+// GroupByLanguage detects the language of every text and returns the DeepL
+// source languages, in order of first appearance, with the indexes of their
+// texts. Texts whose language isn't reliably detected, as is common with
+// short texts, or isn't a DeepL one go to an "auto" group.
+// TranslateTextsByDeepL sends one request per group when translating from
+// "auto".
+func GroupByLanguage(texts []string) ([]string, [][]int) {
+	var (
+		languages []string
+		groups    [][]int
+	)
+	seen := make(map[string]int)
+	for i, text := range texts {
+		lang := "auto"
+		if info := whatlanggo.Detect(text); info.IsReliable() {
+			if code := strings.ToUpper(info.Lang.Iso6391()); supported[code] {
+				lang = code
+			}
+		}
+		n, ok := seen[lang]
+		if !ok {
+			n = len(languages)
+			seen[lang] = n
+			languages = append(languages, lang)
+			groups = append(groups, nil)
+		}
+		groups[n] = append(groups[n], i)
+	}
+	return languages, groups
+}
+
+// translateTexts implements TranslateTextsByDeepL, sending sourceLang as is.
+func translateTexts(ctx context.Context, httpClient *http.Client, sourceLang, targetLang string, texts []string, opts TranslateOptions, proxyURL string, dlSession string) ([]DeepLTranslationResult, error) {
+	alternatives := opts.Alternatives
+	if alternatives == 0 {
+		alternatives = 3
+	} else if alternatives < 0 {
+		alternatives = 0
+	}
+
+	results := make([]DeepLTranslationResult, len(texts))
+
+	// Collect non-empty texts, remembering their position in the input
+	var (
+		items   []TextItem
+		indexes []int
+		joined  strings.Builder
+	)
+	for i, text := range texts {
+		if text == "" {
+			results[i] = DeepLTranslationResult{
+				Code:    http.StatusNotFound,
+				Message: "No text to translate",
+				Err:     errs.New(errs.ErrEmptyText, BackendName, 0, "No text to translate", nil),
+			}
+			continue
+		}
+		items = append(items, TextItem{
+			Text:                text,
+			RequestAlternatives: alternatives,
+		})
+		indexes = append(indexes, i)
+		joined.WriteString(text)
+		joined.WriteString("\n")
+	}
+	if len(items) == 0 {
+		return results, nil
+	}
+
+	// Send regional variants such as EN-GB as a base language with a variant
+	targetLang, variant := splitVariant(targetLang)
+	if opts.RegionalVariant == "" {
+		opts.RegionalVariant = variant
 	}
 
 	// Prepare translation request using new LMT_handle_texts method
 	id := getRandomNumber()
-	iCount := getICount(text)
+	iCount := getICount(joined.String())
 	timestamp := getTimeStamp(iCount)
 
 	postData := &PostData{
@@ -153,11 +279,9 @@ func TranslateByDeepL(ctx context.Context, httpClient *http.Client, sourceLang,
 				SourceLangUserSelected: sourceLang,
 				TargetLang:             targetLang,
 			},
-			Texts: []TextItem{{
-				Text:                text,
-				RequestAlternatives: 3,
-			}},
-			Timestamp: timestamp,
+			Texts:           items,
+			CommonJobParams: commonJobParams(opts),
+			Timestamp:       timestamp,
 		},
 	}
 
@@ -165,56 +289,150 @@ func TranslateByDeepL(ctx context.Context, httpClient *http.Client, sourceLang,
 	postStr := formatPostString(postData)
 	postStr = handlerBodyMethod(id, postStr)
 
+	failAll := func(err *errs.Error) []DeepLTranslationResult {
+		code := err.StatusCode
+		if code == 0 {
+			code = http.StatusServiceUnavailable
+		}
+		for _, i := range indexes {
+			results[i] = DeepLTranslationResult{
+				Code:    code,
+				Message: err.Error(),
+				Err:     err,
+			}
+		}
+		return results
+	}
+
 	// Make translation request
 	result, err := makeRequestWithBody(ctx, httpClient, postStr, proxyURL, dlSession)
 	if err != nil {
-		return DeepLTranslationResult{
-			Code:    http.StatusServiceUnavailable,
-			Message: err.Error(),
-		}, nil
+		var e *errs.Error
+		if !errors.As(err, &e) {
+			e = errs.New(errs.ErrNetwork, BackendName, 0, "", err)
+		}
+		return failAll(e), nil
+	}
+
+	// Check for a JSON-RPC error such as rate limiting or unsupported languages
+	if rpcErr := result.Get("error"); rpcErr.Exists() {
+		return failAll(rpcError(rpcErr)), nil
 	}
 
 	// Process translation results using new format
 	textsArray := result.Get("result.texts").Array()
 	if len(textsArray) == 0 {
-		return DeepLTranslationResult{
-			Code:    http.StatusServiceUnavailable,
-			Message: "Translation failed",
-		}, nil
+		return failAll(errs.New(errs.ErrUnexpectedResponse, BackendName, http.StatusOK, "Translation failed", nil)), nil
 	}
 
-	// Get main translation
-	mainText := textsArray[0].Get("text").String()
-	if mainText == "" {
-		return DeepLTranslationResult{
-			Code:    http.StatusServiceUnavailable,
-			Message: "Translation failed",
-		}, nil
+	// Get detected source language from response
+	detectedLang := result.Get("result.lang").String()
+	if detectedLang != "" {
+		sourceLang = detectedLang
 	}
+	detectedLanguages := parseDetectedLanguages(result.Get("result.detectedLanguages"))
+	method := map[bool]string{true: "Pro", false: "Free"}[dlSession != ""]
 
-	// Get alternatives
-	var alternatives []string
-	alternativesArray := textsArray[0].Get("alternatives").Array()
-	for _, alt := range alternativesArray {
-		altText := alt.Get("text").String()
-		if altText != "" {
-			alternatives = append(alternatives, altText)
+	for n, i := range indexes {
+		// Get main translation
+		var mainText string
+		if n < len(textsArray) {
+			mainText = textsArray[n].Get("text").String()
+		}
+		if mainText == "" {
+			results[i] = DeepLTranslationResult{
+				Code:    http.StatusServiceUnavailable,
+				Message: "Translation failed",
+				Err:     errs.New(errs.ErrUnexpectedResponse, BackendName, http.StatusOK, "Translation failed", nil),
+			}
+			continue
+		}
+
+		// Get alternatives
+		var alternatives []string
+		alternativesArray := textsArray[n].Get("alternatives").Array()
+		for _, alt := range alternativesArray {
+			altText := alt.Get("text").String()
+			if altText != "" {
+				alternatives = append(alternatives, altText)
+			}
+		}
+
+		results[i] = DeepLTranslationResult{
+			Code:         http.StatusOK,
+			ID:           id,
+			Data:         mainText,
+			Alternatives: alternatives,
+			SourceLang:   sourceLang,
+			TargetLang:   targetLang,
+			Method:       method,
+			Detected:     detectedLanguages,
 		}
 	}
+	return results, nil
+}
 
-	// Get detected source language from response
-	detectedLang := result.Get("result.lang").String()
-	if detectedLang != "" {
-		sourceLang = detectedLang
+// rpcError converts a JSON-RPC error object returned by DeepL into an *errs.Error.
+func rpcError(rpcErr gjson.Result) *errs.Error {
+	message := rpcErr.Get("message").String()
+	if message == "" {
+		message = "Translation failed"
+	}
+	lower := strings.ToLower(message)
+	switch {
+	case strings.Contains(lower, "too many requests"):
+		return errs.New(errs.ErrRateLimited, BackendName, http.StatusTooManyRequests, message, nil)
+	case strings.Contains(lower, "not supported"):
+		return errs.New(errs.ErrUnsupportedPair, BackendName, http.StatusOK, message, nil)
+	case strings.Contains(lower, "too long"), strings.Contains(lower, "too large"):
+		return errs.New(errs.ErrTextTooLong, BackendName, http.StatusOK, message, nil)
+	}
+	return errs.New(errs.ErrUnexpectedResponse, BackendName, http.StatusOK, message, nil)
+}
+
+// commonJobParams converts per-request options into job parameters, or nil if none are set.
+func commonJobParams(opts TranslateOptions) *CommonJobParams {
+	var formality string
+	switch strings.ToLower(opts.Formality) {
+	case "formal", "more":
+		formality = "formal"
+	case "informal", "less":
+		formality = "informal"
+	}
+
+	var textType string
+	switch strings.ToLower(opts.TagHandling) {
+	case "html", "xml":
+		textType = "richtext"
 	}
 
-	return DeepLTranslationResult{
-		Code:         http.StatusOK,
-		ID:           id,
-		Data:         mainText,
-		Alternatives: alternatives,
-		SourceLang:   sourceLang,
-		TargetLang:   targetLang,
-		Method:       map[bool]string{true: "Pro", false: "Free"}[dlSession != ""],
-	}, nil
+	if formality == "" && textType == "" && opts.RegionalVariant == "" && opts.Context == "" {
+		return nil
+	}
+	return &CommonJobParams{
+		Formality:       formality,
+		Mode:            "translate",
+		TextType:        textType,
+		RegionalVariant: opts.RegionalVariant,
+		Context:         opts.Context,
+	}
+}
+
+// parseDetectedLanguages reads the language scores reported by DeepL, skipping
+// entries that aren't numbers.
+func parseDetectedLanguages(value gjson.Result) map[string]float64 {
+	if !value.IsObject() {
+		return nil
+	}
+	detected := make(map[string]float64)
+	value.ForEach(func(key, score gjson.Result) bool {
+		if score.Type == gjson.Number {
+			detected[strings.ToUpper(key.String())] = score.Float()
+		}
+		return true
+	})
+	if len(detected) == 0 {
+		return nil
+	}
+	return detected
 }
@@ -9,13 +9,14 @@ type Lang struct {
 
 // CommonJobParams represents common parameters for translation jobs
 type CommonJobParams struct {
-	Formality       string `json:"formality"` // Can be "undefined"
-	TranscribeAs    string `json:"transcribe_as"`
-	Mode            string `json:"mode"`
+	Formality       string `json:"formality,omitempty"` // Can be "undefined"
+	TranscribeAs    string `json:"transcribe_as,omitempty"`
+	Mode            string `json:"mode,omitempty"`
 	WasSpoken       bool   `json:"wasSpoken"`
-	AdvancedMode    bool   `json:"advancedMode"`
-	TextType        string `json:"textType"`
+	AdvancedMode    bool   `json:"advancedMode,omitempty"`
+	TextType        string `json:"textType,omitempty"`
 	RegionalVariant string `json:"regionalVariant,omitempty"`
+	Context         string `json:"context,omitempty"`
 }
 
 // Sentence represents a sentence in the translation request
@@ -42,10 +43,20 @@ type TextItem struct {
 
 // Params represents parameters for translation requests
 type Params struct {
-	Splitting string     `json:"splitting"`
-	Lang      Lang       `json:"lang"`
-	Texts     []TextItem `json:"texts"`
-	Timestamp int64      `json:"timestamp"`
+	Splitting       string           `json:"splitting"`
+	Lang            Lang             `json:"lang"`
+	Texts           []TextItem       `json:"texts"`
+	CommonJobParams *CommonJobParams `json:"commonJobParams,omitempty"`
+	Timestamp       int64            `json:"timestamp"`
+}
+
+// TranslateOptions holds optional per-request translation settings
+type TranslateOptions struct {
+	Formality       string // "formal" or "informal", also accepts "more" and "less"
+	RegionalVariant string // Target variant such as "en-US" or "pt-BR"
+	Alternatives    int    // Number of alternatives, zero for the default of 3, negative for none
+	TagHandling     string // "html" or "xml" when texts contain markup
+	Context         string // Text that helps interpret the texts, not translated
 }
 
 // LegacyParams represents the old parameters structure for jobs (kept for compatibility)
@@ -112,12 +123,14 @@ type SentenceResponse struct {
 
 // DeepLTranslationResult represents the final translation result
 type DeepLTranslationResult struct {
-	Code         int      `json:"code"`
-	ID           int64    `json:"id"`
-	Message      string   `json:"message,omitempty"`
-	Data         string   `json:"data"`         // The primary translated text
-	Alternatives []string `json:"alternatives"` // Other possible translations
-	SourceLang   string   `json:"source_lang"`
-	TargetLang   string   `json:"target_lang"`
-	Method       string   `json:"method"`
+	Code         int                `json:"code"`
+	ID           int64              `json:"id"`
+	Message      string             `json:"message,omitempty"`
+	Data         string             `json:"data"`         // The primary translated text
+	Alternatives []string           `json:"alternatives"` // Other possible translations
+	SourceLang   string             `json:"source_lang"`
+	TargetLang   string             `json:"target_lang"`
+	Method       string             `json:"method"`
+	Detected     map[string]float64 `json:"detected,omitempty"` // Source language scores, when reported
+	Err          error              `json:"-"`                  // Typed error when Code isn't http.StatusOK
 }
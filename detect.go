@@ -0,0 +1,83 @@
+package gt
+
+import (
+	"context"
+	"strings"
+
+	"github.com/abadojack/whatlanggo"
+)
+
+// Detector is implemented by translators that can detect the language of a text.
+type Detector interface {
+	Detect(ctx context.Context, text string) ([]DetectedLanguage, error)
+}
+
+// DetectedLanguage is a candidate language of a text.
+type DetectedLanguage struct {
+	Language   string  `json:"language"`   // Lowercase ISO 639-1 code, or ISO 639-3 when there is none
+	Confidence float64 `json:"confidence"` // Between 0 and 1
+}
+
+// offlineDetector detects languages locally with whatlanggo.
+type offlineDetector struct{}
+
+// NewOfflineDetector creates a Detector that works without network access.
+// It returns a single candidate, with the confidence whatlanggo gives it,
+// or none when it can't tell; the confidence of a language identified by its
+// script alone, such as Chinese, is 1.
+func NewOfflineDetector() Detector {
+	return offlineDetector{}
+}
+
+func (offlineDetector) Detect(ctx context.Context, text string) ([]DetectedLanguage, error) {
+	info := whatlanggo.Detect(text)
+	if info.Lang < 0 {
+		return nil, nil
+	}
+	code := info.Lang.Iso6391()
+	if code == "" {
+		code = info.Lang.Iso6393()
+	}
+	return []DetectedLanguage{{Language: code, Confidence: info.Confidence}}, nil
+}
+
+// Detect implements Detector. Google reports a single language without a
+// score, which is returned with a confidence of 1.
+func (g *googleTranslateAdapter) Detect(ctx context.Context, text string) ([]DetectedLanguage, error) {
+	iso, err := g.client.Detect(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	return []DetectedLanguage{{Language: strings.ToLower(iso), Confidence: 1}}, nil
+}
+
+// Detect implements Detector.
+func (d *deeplAdapter) Detect(ctx context.Context, text string) ([]DetectedLanguage, error) {
+	candidates, err := d.client.Detect(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	detected := make([]DetectedLanguage, len(candidates))
+	for i, candidate := range candidates {
+		detected[i] = DetectedLanguage{Language: strings.ToLower(candidate.Language), Confidence: candidate.Confidence}
+	}
+	return detected, nil
+}
+
+// Detect detects the language of text with the default translator, or
+// offline if it can't detect languages. Candidates are ranked most likely first.
+func Detect(ctx context.Context, text string) ([]DetectedLanguage, error) {
+	detector, ok := getTranslator().(Detector)
+	if !ok {
+		detector = NewOfflineDetector()
+	}
+	return DetectWith(ctx, detector, text)
+}
+
+// DetectWith detects the language of text using a specific detector.
+func DetectWith(ctx context.Context, detector Detector, text string) ([]DetectedLanguage, error) {
+	if text == "" {
+		return nil, errTextRequired()
+	}
+	return detector.Detect(ctx, text)
+}
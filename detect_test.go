@@ -0,0 +1,36 @@
+package gt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOfflineDetector(t *testing.T) {
+	detected, err := DetectWith(context.Background(), NewOfflineDetector(), "Ceci est un texte écrit en français, assez long pour être reconnu.")
+	assert.NoError(t, err)
+	assert.Len(t, detected, 1)
+	assert.Equal(t, "fr", detected[0].Language)
+	assert.Greater(t, detected[0].Confidence, 0.0)
+	assert.LessOrEqual(t, detected[0].Confidence, 1.0)
+
+	// Short texts get a low confidence rather than made-up alternatives
+	detected, err = DetectWith(context.Background(), NewOfflineDetector(), "Hello world, how are you doing today?")
+	assert.NoError(t, err)
+	assert.Len(t, detected, 1)
+	assert.Less(t, detected[0].Confidence, 0.5)
+}
+
+func TestOfflineDetectorScript(t *testing.T) {
+	detected, err := DetectWith(context.Background(), NewOfflineDetector(), "这是第一句话。")
+	assert.NoError(t, err)
+	assert.Len(t, detected, 1)
+	assert.Equal(t, "zh", detected[0].Language)
+	assert.Equal(t, 1.0, detected[0].Confidence)
+}
+
+func TestDetectWithEmptyText(t *testing.T) {
+	_, err := DetectWith(context.Background(), NewOfflineDetector(), "")
+	assert.ErrorIs(t, err, ErrEmptyText)
+}
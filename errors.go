@@ -0,0 +1,47 @@
+package gt
+
+import (
+	"errors"
+
+	"gopkg.gilang.dev/translator/v2/errs"
+)
+
+// Error describes a failed translation request. See the errs package.
+type Error = errs.Error
+
+// Sentinel errors shared by every backend, usable with errors.Is.
+var (
+	ErrRateLimited        = errs.ErrRateLimited
+	ErrInvalidLanguage    = errs.ErrInvalidLanguage
+	ErrUnsupportedPair    = errs.ErrUnsupportedPair
+	ErrUnexpectedResponse = errs.ErrUnexpectedResponse
+	ErrNetwork            = errs.ErrNetwork
+	ErrTextTooLong        = errs.ErrTextTooLong
+	ErrEmptyText          = errs.ErrEmptyText
+	ErrCircuitOpen        = errs.ErrCircuitOpen
+)
+
+// errTextRequired reports a missing text.
+func errTextRequired() error {
+	return errs.New(ErrEmptyText, "", 0, "Text Value is required!", nil)
+}
+
+// errLanguageRequired reports a missing language for field ("From" or "To").
+func errLanguageRequired(field string) error {
+	return errs.New(ErrInvalidLanguage, "", 0, field+" Value is required!", nil)
+}
+
+// errLanguageInvalid reports a language for field that can't be parsed.
+func errLanguageInvalid(field string, cause error) error {
+	return errs.New(ErrInvalidLanguage, "", 0, field+" Value isn't valid!", cause)
+}
+
+// IsRetryable reports whether err is a transient failure worth retrying,
+// possibly on another backend: rate limiting, network and parse failures, or
+// an open circuit breaker.
+func IsRetryable(err error) bool {
+	return errors.Is(err, ErrRateLimited) ||
+		errors.Is(err, ErrNetwork) ||
+		errors.Is(err, ErrUnexpectedResponse) ||
+		errors.Is(err, ErrCircuitOpen)
+}
@@ -0,0 +1,30 @@
+package gt
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.gilang.dev/translator/v2/params"
+)
+
+func TestValidationErrors(t *testing.T) {
+	ctx := context.Background()
+	stub := &stubTranslator{}
+
+	_, err := TranslateWith(ctx, stub, "", params.INDONESIAN)
+	assert.ErrorIs(t, err, ErrEmptyText)
+	assert.EqualError(t, err, "Text Value is required!")
+
+	_, err = TranslateWith(ctx, stub, "Hello", "")
+	assert.ErrorIs(t, err, ErrInvalidLanguage)
+
+	_, err = ManualTranslate(ctx, "Hello", "not a language", params.INDONESIAN)
+	assert.ErrorIs(t, err, ErrInvalidLanguage)
+
+	var e *Error
+	assert.True(t, errors.As(err, &e))
+	assert.Empty(t, e.Backend)
+	assert.Zero(t, stub.calls)
+}
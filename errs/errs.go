@@ -0,0 +1,144 @@
+// Package errs defines the errors returned by every translation backend.
+//
+// All backends report failures as *Error values that wrap one of the sentinel
+// errors below, so callers can branch with errors.Is and inspect details with
+// errors.As regardless of which backend produced them.
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrRateLimited is returned when a backend rejects requests for being too frequent.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrInvalidLanguage is returned when a language code can't be parsed.
+	ErrInvalidLanguage = errors.New("invalid language")
+	// ErrUnsupportedPair is returned when a backend doesn't support the language pair.
+	ErrUnsupportedPair = errors.New("unsupported language pair")
+	// ErrUnexpectedResponse is returned when a backend response can't be understood.
+	ErrUnexpectedResponse = errors.New("unexpected response")
+	// ErrNetwork is returned when a backend can't be reached.
+	ErrNetwork = errors.New("network error")
+	// ErrTextTooLong is returned when the text exceeds what a backend accepts.
+	ErrTextTooLong = errors.New("text too long")
+	// ErrEmptyText is returned when there is no text to translate.
+	ErrEmptyText = errors.New("empty text")
+	// ErrCircuitOpen is returned without calling a backend while its circuit breaker is open.
+	ErrCircuitOpen = errors.New("circuit breaker is open")
+)
+
+// Error describes a failed translation request.
+type Error struct {
+	Kind       error         // One of the sentinel errors of this package
+	Backend    string        // Backend name, empty for client-side validation
+	StatusCode int           // HTTP status code, zero if no response was received
+	Message    string        // Human readable description, defaults to Kind
+	Err        error         // Underlying cause, if any
+	RetryAfter time.Duration // Delay requested by the backend's Retry-After header, if any
+}
+
+// New creates an Error of the given kind.
+func New(kind error, backend string, statusCode int, message string, cause error) *Error {
+	return &Error{
+		Kind:       kind,
+		Backend:    backend,
+		StatusCode: statusCode,
+		Message:    message,
+		Err:        cause,
+	}
+}
+
+// FromStatus creates an Error whose kind is derived from an HTTP status code.
+func FromStatus(backend string, statusCode int, message string) *Error {
+	var kind error
+	switch statusCode {
+	case http.StatusTooManyRequests:
+		kind = ErrRateLimited
+	case http.StatusRequestEntityTooLarge, http.StatusRequestURITooLong:
+		kind = ErrTextTooLong
+	default:
+		kind = ErrUnexpectedResponse
+	}
+	if message == "" {
+		message = fmt.Sprintf("request failed with status code %d", statusCode)
+	}
+	return New(kind, backend, statusCode, message, nil)
+}
+
+// ParseRetryAfter parses the value of a Retry-After header, given either in
+// seconds or as an HTTP date. It returns zero if value is empty or invalid.
+func ParseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	if e.Backend != "" {
+		b.WriteString(e.Backend)
+		b.WriteString(": ")
+	}
+	switch {
+	case e.Message != "":
+		b.WriteString(e.Message)
+	case e.Kind != nil:
+		b.WriteString(e.Kind.Error())
+	default:
+		b.WriteString("translation failed")
+	}
+	if e.Err != nil {
+		b.WriteString(": ")
+		b.WriteString(e.Err.Error())
+	}
+	return b.String()
+}
+
+// Unwrap returns the error kind and the underlying cause.
+func (e *Error) Unwrap() []error {
+	var errs []error
+	if e.Kind != nil {
+		errs = append(errs, e.Kind)
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	return errs
+}
+
+// StatusCode returns the HTTP status code carried by err, or zero.
+func StatusCode(err error) int {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.StatusCode
+	}
+	return 0
+}
+
+// Backend returns the backend name carried by err, or an empty string.
+func Backend(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Backend
+	}
+	return ""
+}
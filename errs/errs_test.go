@@ -0,0 +1,75 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestErrorIsKindAndCause(t *testing.T) {
+	cause := errors.New("connection reset")
+	err := fmt.Errorf("wrapped: %w", New(ErrNetwork, "google", 0, "bad network", cause))
+
+	if !errors.Is(err, ErrNetwork) {
+		t.Error("expected error to match ErrNetwork")
+	}
+	if !errors.Is(err, cause) {
+		t.Error("expected error to match its cause")
+	}
+	if errors.Is(err, ErrRateLimited) {
+		t.Error("did not expect error to match ErrRateLimited")
+	}
+	if Backend(err) != "google" {
+		t.Errorf("Expected backend google, got %q", Backend(err))
+	}
+
+	expected := "wrapped: google: bad network: connection reset"
+	if err.Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, err.Error())
+	}
+}
+
+func TestFromStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		kind   error
+	}{
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusRequestEntityTooLarge, ErrTextTooLong},
+		{http.StatusRequestURITooLong, ErrTextTooLong},
+		{http.StatusInternalServerError, ErrUnexpectedResponse},
+	}
+	for _, tt := range tests {
+		err := FromStatus("deepl", tt.status, "")
+		if !errors.Is(err, tt.kind) {
+			t.Errorf("status %d: expected %v, got %v", tt.status, tt.kind, err.Kind)
+		}
+		if StatusCode(err) != tt.status {
+			t.Errorf("status %d: got status code %d", tt.status, StatusCode(err))
+		}
+	}
+}
+
+func TestErrorDefaultMessage(t *testing.T) {
+	err := New(ErrEmptyText, "", 0, "", nil)
+	if err.Error() != "empty text" {
+		t.Errorf("Expected %q, got %q", "empty text", err.Error())
+	}
+	if StatusCode(errors.New("plain")) != 0 {
+		t.Error("Expected zero status code for a plain error")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if d := ParseRetryAfter("120"); d != 120*time.Second {
+		t.Errorf("Expected 120s, got %v", d)
+	}
+	if d := ParseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)); d <= 59*time.Minute {
+		t.Errorf("Expected about an hour, got %v", d)
+	}
+	if d := ParseRetryAfter("soon"); d != 0 {
+		t.Errorf("Expected zero, got %v", d)
+	}
+}
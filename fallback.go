@@ -0,0 +1,89 @@
+package gt
+
+import (
+	"context"
+	"errors"
+)
+
+// fallbackTranslator tries each translator in order until one succeeds.
+type fallbackTranslator struct {
+	translators []Translator
+}
+
+// NewFallbackTranslator creates a Translator that uses primary and moves on to
+// the next secondary translator whenever one fails with a retryable error
+// (see IsRetryable). Translated.Backend tells which backend answered.
+// If every translator fails, the joined errors are returned.
+func NewFallbackTranslator(primary Translator, secondary ...Translator) Translator {
+	translators := make([]Translator, 0, len(secondary)+1)
+	translators = append(translators, primary)
+	translators = append(translators, secondary...)
+	return &fallbackTranslator{translators: translators}
+}
+
+func (f *fallbackTranslator) Translate(ctx context.Context, text, from, to string) (*Translated, error) {
+	var failed []error
+	for _, translator := range f.translators {
+		result, err := translator.Translate(ctx, text, from, to)
+		if err == nil {
+			return result, nil
+		}
+		failed = append(failed, err)
+		if !IsRetryable(err) || ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, errors.Join(failed...)
+}
+
+// TranslateBatch implements BatchTranslator, passing only the texts that
+// failed with a retryable error on to the next translator.
+func (f *fallbackTranslator) TranslateBatch(ctx context.Context, texts []string, from, to string) ([]BatchResult, error) {
+	results := make([]BatchResult, len(texts))
+	failures := make([][]error, len(texts))
+	pending := make([]int, len(texts))
+	for i := range texts {
+		pending[i] = i
+	}
+
+	for _, translator := range f.translators {
+		if len(pending) == 0 || ctx.Err() != nil {
+			break
+		}
+		batch := make([]string, len(pending))
+		for n, i := range pending {
+			batch[n] = texts[i]
+		}
+
+		batchResults, err := batchWith(ctx, translator, batch, from, to)
+		var next []int
+		for n, i := range pending {
+			itemErr := err
+			if err == nil {
+				results[i] = batchResults[n]
+				itemErr = batchResults[n].Err
+			}
+			if itemErr == nil {
+				continue
+			}
+			failures[i] = append(failures[i], itemErr)
+			if IsRetryable(itemErr) {
+				next = append(next, i)
+			}
+		}
+		pending = next
+	}
+	if err := ctx.Err(); err != nil {
+		// The texts left weren't given to the next translator
+		for _, i := range pending {
+			failures[i] = append(failures[i], err)
+		}
+	}
+
+	for i, failed := range failures {
+		if results[i].Translated == nil && len(failed) > 0 {
+			results[i] = BatchResult{Err: errors.Join(failed...)}
+		}
+	}
+	return results, nil
+}
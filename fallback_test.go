@@ -0,0 +1,88 @@
+package gt
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.gilang.dev/translator/v2/errs"
+)
+
+func TestFallbackTranslator(t *testing.T) {
+	primary := &stubTranslator{fn: func(text, from, to string) (*Translated, error) {
+		return nil, errs.FromStatus("primary", 429, "")
+	}}
+	secondary := &stubTranslator{fn: func(text, from, to string) (*Translated, error) {
+		return &Translated{Text: "halo", Backend: "secondary"}, nil
+	}}
+
+	translator := NewFallbackTranslator(primary, secondary)
+	result, err := translator.Translate(context.Background(), "hello", "en", "id")
+	assert.NoError(t, err)
+	assert.Equal(t, "secondary", result.Backend)
+	assert.Equal(t, int32(1), primary.calls)
+	assert.Equal(t, int32(1), secondary.calls)
+}
+
+func TestFallbackTranslatorNonRetryable(t *testing.T) {
+	primary := &stubTranslator{fn: func(text, from, to string) (*Translated, error) {
+		return nil, errs.New(ErrInvalidLanguage, "primary", 0, "", nil)
+	}}
+	secondary := &stubTranslator{}
+
+	_, err := NewFallbackTranslator(primary, secondary).Translate(context.Background(), "hello", "en", "xx")
+	assert.ErrorIs(t, err, ErrInvalidLanguage)
+	assert.Zero(t, secondary.calls)
+}
+
+func TestFallbackTranslatorAllFail(t *testing.T) {
+	primary := &stubTranslator{fn: func(text, from, to string) (*Translated, error) {
+		return nil, errs.New(ErrNetwork, "primary", 0, "", nil)
+	}}
+	secondary := &stubTranslator{fn: func(text, from, to string) (*Translated, error) {
+		return nil, errs.FromStatus("secondary", 429, "")
+	}}
+
+	_, err := NewFallbackTranslator(primary, secondary).Translate(context.Background(), "hello", "en", "id")
+	assert.ErrorIs(t, err, ErrNetwork)
+	assert.ErrorIs(t, err, ErrRateLimited)
+}
+
+func TestFallbackTranslatorBatch(t *testing.T) {
+	primary := &stubTranslator{fn: func(text, from, to string) (*Translated, error) {
+		switch text {
+		case "retry":
+			return nil, errs.New(ErrNetwork, "primary", 0, "", nil)
+		case "invalid":
+			return nil, errs.New(ErrTextTooLong, "primary", 0, "", nil)
+		}
+		return &Translated{Text: text, Backend: "primary"}, nil
+	}}
+	secondary := &stubTranslator{fn: func(text, from, to string) (*Translated, error) {
+		return &Translated{Text: text, Backend: "secondary"}, nil
+	}}
+
+	translator := NewFallbackTranslator(primary, secondary)
+	results, err := TranslateBatchWith(context.Background(), translator, []string{"ok", "retry", "invalid"}, "en", "id")
+	assert.NoError(t, err)
+	assert.Equal(t, "primary", results[0].Translated.Backend)
+	assert.Equal(t, "secondary", results[1].Translated.Backend)
+	assert.True(t, errors.Is(results[2].Err, ErrTextTooLong))
+	assert.Equal(t, int32(1), secondary.calls)
+}
+
+func TestFallbackTranslatorBatchCanceled(t *testing.T) {
+	primary, secondary := &stubTranslator{}, &stubTranslator{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := NewFallbackTranslator(primary, secondary).(BatchTranslator).TranslateBatch(ctx, []string{"a", "b"}, "en", "id")
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	for _, result := range results {
+		assert.Nil(t, result.Translated)
+		assert.ErrorIs(t, result.Err, context.Canceled)
+	}
+	assert.Zero(t, primary.calls)
+}
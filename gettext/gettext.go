@@ -0,0 +1,254 @@
+// Package gettext translates gettext PO and POT catalogs, filling the
+// missing translations of their messages and marking them fuzzy so that
+// translators review them.
+package gettext
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	gt "gopkg.gilang.dev/translator/v2"
+	"gopkg.gilang.dev/translator/v2/internal/placeholder"
+)
+
+// Fuzzy is the flag of the messages needing review, which machine
+// translations get.
+const Fuzzy = "fuzzy"
+
+// Placeholders match the format directives left untranslated by default:
+// printf directives, as in c-format or python-format messages, and Python
+// brace directives, as in python-brace-format messages.
+var Placeholders = []*regexp.Regexp{
+	placeholder.Printf,
+	regexp.MustCompile(`\{[A-Za-z0-9_]*(?:![rsa])?(?::[^{}]*)?\}`),
+}
+
+// PluralForms holds the Plural-Forms header of common languages, as given
+// by the gettext manual. Translated catalogs without one get the forms of
+// their language, or those of English.
+var PluralForms = map[string]string{
+	"ar":    "nplurals=6; plural=(n==0 ? 0 : n==1 ? 1 : n==2 ? 2 : n%100>=3 && n%100<=10 ? 3 : n%100>=11 ? 4 : 5);",
+	"be":    "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"cs":    "nplurals=3; plural=(n==1) ? 0 : (n>=2 && n<=4) ? 1 : 2;",
+	"da":    "nplurals=2; plural=(n != 1);",
+	"de":    "nplurals=2; plural=(n != 1);",
+	"el":    "nplurals=2; plural=(n != 1);",
+	"en":    "nplurals=2; plural=(n != 1);",
+	"es":    "nplurals=2; plural=(n != 1);",
+	"et":    "nplurals=2; plural=(n != 1);",
+	"fi":    "nplurals=2; plural=(n != 1);",
+	"fr":    "nplurals=2; plural=(n > 1);",
+	"ga":    "nplurals=5; plural=(n==1 ? 0 : n==2 ? 1 : n>=3 && n<=6 ? 2 : n>=7 && n<=10 ? 3 : 4);",
+	"he":    "nplurals=2; plural=(n != 1);",
+	"hi":    "nplurals=2; plural=(n != 1);",
+	"hr":    "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"hu":    "nplurals=2; plural=(n != 1);",
+	"id":    "nplurals=1; plural=0;",
+	"it":    "nplurals=2; plural=(n != 1);",
+	"ja":    "nplurals=1; plural=0;",
+	"ko":    "nplurals=1; plural=0;",
+	"lt":    "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"lv":    "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n != 0 ? 1 : 2);",
+	"ms":    "nplurals=1; plural=0;",
+	"nb":    "nplurals=2; plural=(n != 1);",
+	"nl":    "nplurals=2; plural=(n != 1);",
+	"pl":    "nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"pt":    "nplurals=2; plural=(n != 1);",
+	"pt_BR": "nplurals=2; plural=(n > 1);",
+	"ro":    "nplurals=3; plural=(n==1 ? 0 : (n==0 || (n%100 > 0 && n%100 < 20)) ? 1 : 2);",
+	"ru":    "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"sk":    "nplurals=3; plural=(n==1) ? 0 : (n>=2 && n<=4) ? 1 : 2;",
+	"sl":    "nplurals=4; plural=(n%100==1 ? 0 : n%100==2 ? 1 : n%100==3 || n%100==4 ? 2 : 3);",
+	"sr":    "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"sv":    "nplurals=2; plural=(n != 1);",
+	"th":    "nplurals=1; plural=0;",
+	"tr":    "nplurals=2; plural=(n > 1);",
+	"uk":    "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"vi":    "nplurals=1; plural=0;",
+	"zh":    "nplurals=1; plural=0;",
+}
+
+var nplurals = regexp.MustCompile(`nplurals\s*=\s*(\d+)`)
+
+// Translator translates PO and POT catalogs with a gt.Translator.
+type Translator struct {
+	translator   gt.Translator
+	placeholders []*regexp.Regexp
+	overwrite    bool
+}
+
+// Option is a functional option for configuring New.
+type Option func(*Translator)
+
+// WithPlaceholders replaces the patterns of the texts left untranslated,
+// Placeholders by default.
+func WithPlaceholders(patterns ...*regexp.Regexp) Option {
+	return func(t *Translator) {
+		t.placeholders = patterns
+	}
+}
+
+// WithOverwrite translates again the messages having a translation, which
+// are kept by default.
+func WithOverwrite() Option {
+	return func(t *Translator) {
+		t.overwrite = true
+	}
+}
+
+// New creates a Translator of catalogs using translator.
+func New(translator gt.Translator, opts ...Option) *Translator {
+	t := &Translator{
+		translator:   translator,
+		placeholders: Placeholders,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Translate fills the missing translations of f, translating each msgid from
+// the from language to the to language, and flags them fuzzy. A message with
+// a plural gets its translated msgid in msgstr[0] and its translated
+// msgid_plural in the other forms, as many as the Plural-Forms header of f
+// tells; languages with a single form get the plural. Comments, references
+// and obsolete messages are kept as is. A catalog without Language or with
+// template Plural-Forms, such as a POT file, gets those of to. All texts are
+// translated in a single batch.
+func (t *Translator) Translate(ctx context.Context, f *File, from, to string) error {
+	t.updateHeader(f, to)
+	forms := 2
+	if m := nplurals.FindStringSubmatch(f.HeaderField("Plural-Forms")); m != nil {
+		if n, err := strconv.Atoi(m[1]); err == nil && n > 0 {
+			forms = n
+		}
+	}
+
+	var (
+		entries []*Entry
+		texts   []string
+		tokens  [][]string
+		indexes = make(map[string]int)
+	)
+	add := func(s string) {
+		if _, ok := indexes[s]; !ok {
+			text, protected := placeholder.Protect(s, t.placeholders...)
+			indexes[s] = len(texts)
+			texts = append(texts, text)
+			tokens = append(tokens, protected)
+		}
+	}
+	for _, e := range f.Entries {
+		if e.IsHeader() || e.Obsolete || e.Translated() && !t.overwrite {
+			continue
+		}
+		entries = append(entries, e)
+		add(e.ID)
+		if e.IDPlural != "" {
+			add(e.IDPlural)
+		}
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	// Texts that are only placeholders are copied
+	var (
+		batch   []string
+		batched []int
+	)
+	translated := make([]string, len(texts))
+	for i, text := range texts {
+		if placeholder.Empty(text) {
+			translated[i] = placeholder.Restore(text, tokens[i])
+			continue
+		}
+		batch = append(batch, text)
+		batched = append(batched, i)
+	}
+	if len(batch) > 0 {
+		results, err := gt.TranslateBatchWith(ctx, t.translator, batch, from, to)
+		if err != nil {
+			return err
+		}
+		for n, r := range results {
+			if r.Err != nil {
+				return r.Err
+			}
+			i := batched[n]
+			translated[i] = placeholder.Restore(r.Translated.Text, tokens[i])
+		}
+	}
+	translation := func(s string) string {
+		return keepNewlines(s, translated[indexes[s]])
+	}
+
+	for _, e := range entries {
+		if e.IDPlural == "" {
+			e.Str = translation(e.ID)
+		} else {
+			e.StrPlural = make([]string, forms)
+			for n := range e.StrPlural {
+				if n == 0 && forms > 1 {
+					e.StrPlural[n] = translation(e.ID)
+				} else {
+					e.StrPlural[n] = translation(e.IDPlural)
+				}
+			}
+		}
+		e.AddFlag(Fuzzy)
+	}
+	return nil
+}
+
+// updateHeader sets the Language, Plural-Forms and charset of f for a
+// translation to language when they are missing or template values.
+func (t *Translator) updateHeader(f *File, language string) {
+	if f.HeaderField("Language") == "" {
+		f.SetHeaderField("Language", locale(language))
+	}
+	language = locale(f.HeaderField("Language"))
+	if forms := f.HeaderField("Plural-Forms"); forms == "" || strings.Contains(forms, "INTEGER") {
+		forms, ok := PluralForms[language]
+		if !ok {
+			base, _, _ := strings.Cut(language, "_")
+			if forms, ok = PluralForms[strings.ToLower(base)]; !ok {
+				forms = PluralForms["en"]
+			}
+		}
+		f.SetHeaderField("Plural-Forms", forms)
+	}
+	if contentType := f.HeaderField("Content-Type"); contentType == "" || strings.Contains(contentType, "CHARSET") {
+		f.SetHeaderField("Content-Type", "text/plain; charset=UTF-8")
+	}
+}
+
+// locale converts a BCP 47 tag such as "pt-BR" into the ll_CC form of
+// gettext, "pt_BR".
+func locale(tag string) string {
+	parts := strings.FieldsFunc(tag, func(r rune) bool { return r == '-' || r == '_' })
+	for i, part := range parts {
+		switch {
+		case i == 0:
+			parts[i] = strings.ToLower(part)
+		case len(part) == 2:
+			parts[i] = strings.ToUpper(part)
+		}
+	}
+	return strings.Join(parts, "_")
+}
+
+// keepNewlines gives translation the leading and trailing line breaks of
+// source, which gettext requires msgid and msgstr to share.
+func keepNewlines(source, translation string) string {
+	translation = strings.Trim(translation, "\n")
+	lead := len(source) - len(strings.TrimLeft(source, "\n"))
+	trail := len(source) - len(strings.TrimRight(source, "\n"))
+	if lead == len(source) {
+		return source
+	}
+	return source[:lead] + translation + source[len(source)-trail:]
+}
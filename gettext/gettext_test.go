@@ -0,0 +1,112 @@
+package gettext
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.gilang.dev/translator/v2/internal/translatortest"
+)
+
+const template = `msgid ""
+msgstr ""
+"Language: \n"
+"Plural-Forms: nplurals=INTEGER; plural=EXPRESSION;\n"
+"Content-Type: text/plain; charset=CHARSET\n"
+
+#: main.c:1
+#, c-format
+msgid "Hello, %s!"
+msgstr ""
+
+msgid "Done"
+msgstr "Selesai"
+
+#, python-brace-format
+msgid "{count} file"
+msgid_plural "{count} files"
+msgstr[0] ""
+msgstr[1] ""
+
+msgid "%s"
+msgstr ""
+
+msgid "Hello, %s!\n"
+msgstr ""
+
+#~ msgid "Old"
+#~ msgstr ""
+`
+
+func TestTranslate(t *testing.T) {
+	f, err := Parse([]byte(template))
+	require.NoError(t, err)
+	u := &translatortest.Upper{}
+	require.NoError(t, New(u).Translate(context.Background(), f, "en", "ru"))
+
+	want := `msgid ""
+msgstr ""
+"Language: ru\n"
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && "
+"n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+
+#: main.c:1
+#, c-format, fuzzy
+msgid "Hello, %s!"
+msgstr "HELLO, %s!"
+
+msgid "Done"
+msgstr "Selesai"
+
+#, python-brace-format, fuzzy
+msgid "{count} file"
+msgid_plural "{count} files"
+msgstr[0] "{count} FILE"
+msgstr[1] "{count} FILES"
+msgstr[2] "{count} FILES"
+
+#, fuzzy
+msgid "%s"
+msgstr "%s"
+
+#, fuzzy
+msgid "Hello, %s!\n"
+msgstr "HELLO, %s!\n"
+
+#~ msgid "Old"
+#~ msgstr ""
+`
+	assert.Equal(t, want, string(f.Bytes()))
+	assert.ElementsMatch(t, []string{"Hello, ⟦0⟧!", "⟦0⟧ file", "⟦0⟧ files", "Hello, ⟦0⟧!\n"}, u.Texts())
+}
+
+func TestLocale(t *testing.T) {
+	for tag, want := range map[string]string{"pt-BR": "pt_BR", "pt_br": "pt_BR", "DE": "de", "zh-Hans": "zh_Hans", "": ""} {
+		assert.Equal(t, want, locale(tag), tag)
+	}
+}
+
+func TestTranslateSingleForm(t *testing.T) {
+	f, err := Parse([]byte("msgid \"%d file\"\nmsgid_plural \"%d files\"\nmsgstr[0] \"\"\nmsgstr[1] \"\"\n\nmsgid \"Done\"\nmsgstr \"Fertig\"\n"))
+	require.NoError(t, err)
+	require.NoError(t, New(&translatortest.Upper{}).Translate(context.Background(), f, "en", "id-ID"), "header is added")
+
+	assert.Equal(t, "nplurals=1; plural=0;", f.HeaderField("Plural-Forms"))
+	assert.Equal(t, "id_ID", f.HeaderField("Language"))
+	assert.Equal(t, []string{"%d FILES"}, f.Entries[1].StrPlural)
+	assert.Equal(t, "Fertig", f.Entries[2].Str)
+
+	require.NoError(t, New(&translatortest.Upper{}, WithOverwrite()).Translate(context.Background(), f, "en", "id"))
+	assert.Equal(t, "DONE", f.Entries[2].Str)
+}
+
+func TestTranslateError(t *testing.T) {
+	f, err := Parse([]byte("msgid \"Hi\"\nmsgstr \"\"\n"))
+	require.NoError(t, err)
+	u := &translatortest.Upper{Err: errors.New("backend down")}
+	assert.ErrorIs(t, New(u).Translate(context.Background(), f, "en", "id"), u.Err)
+	assert.Empty(t, f.Entries[1].Str)
+}
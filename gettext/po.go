@@ -0,0 +1,372 @@
+package gettext
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// lineWidth is the width gettext tools wrap strings at.
+const lineWidth = 79
+
+// File is a PO or POT catalog.
+type File struct {
+	Entries []*Entry
+}
+
+// Entry is a message of a catalog, or its header when ID and Context are
+// empty.
+type Entry struct {
+	// Comments are the comment lines other than flags, with their "#"
+	// prefix: translator comments ("# "), extracted comments ("#."),
+	// references ("#:") and previous messages ("#|").
+	Comments  []string
+	Flags     []string // Such as "fuzzy" or "c-format"
+	Context   string   // msgctxt
+	ID        string   // msgid
+	IDPlural  string   // msgid_plural
+	Str       string   // msgstr, for messages without plural
+	StrPlural []string // msgstr[n], for messages with a plural
+	Obsolete  bool     // Entries commented out with "#~"
+}
+
+// IsHeader tells whether e is the header of its catalog.
+func (e *Entry) IsHeader() bool {
+	return e.ID == "" && e.Context == ""
+}
+
+// Translated tells whether e has a translation.
+func (e *Entry) Translated() bool {
+	if e.IDPlural == "" {
+		return e.Str != ""
+	}
+	for _, s := range e.StrPlural {
+		if s != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// HasFlag tells whether e has flag.
+func (e *Entry) HasFlag(flag string) bool {
+	for _, f := range e.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// AddFlag adds flag to e, unless it has it already.
+func (e *Entry) AddFlag(flag string) {
+	if !e.HasFlag(flag) {
+		e.Flags = append(e.Flags, flag)
+	}
+}
+
+// Header returns the header entry of f, or nil if it has none.
+func (f *File) Header() *Entry {
+	for _, e := range f.Entries {
+		if e.IsHeader() && !e.Obsolete {
+			return e
+		}
+	}
+	return nil
+}
+
+// HeaderField returns the value of the header field name, such as
+// "Language" or "Plural-Forms", or "" if there is none.
+func (f *File) HeaderField(name string) string {
+	header := f.Header()
+	if header == nil {
+		return ""
+	}
+	for _, line := range strings.Split(header.Str, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(key), name) {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// SetHeaderField sets the header field name to value, adding the field, and
+// the header, if missing.
+func (f *File) SetHeaderField(name, value string) {
+	header := f.Header()
+	if header == nil {
+		header = &Entry{}
+		f.Entries = append([]*Entry{header}, f.Entries...)
+	}
+	lines := strings.SplitAfter(header.Str, "\n")
+	for i, line := range lines {
+		key, _, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(key), name) {
+			lines[i] = name + ": " + value + "\n"
+			header.Str = strings.Join(lines, "")
+			return
+		}
+	}
+	if header.Str != "" && !strings.HasSuffix(header.Str, "\n") {
+		header.Str += "\n"
+	}
+	header.Str += name + ": " + value + "\n"
+}
+
+// Parse parses a PO or POT catalog.
+func Parse(data []byte) (*File, error) {
+	p := &parser{file: &File{}}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for scanner.Scan() {
+		p.line++
+		if err := p.parseLine(strings.TrimSpace(scanner.Text())); err != nil {
+			return nil, fmt.Errorf("gettext: line %d: %w", p.line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("gettext: %w", err)
+	}
+	p.end()
+	return p.file, nil
+}
+
+// parser parses a catalog line by line.
+type parser struct {
+	file    *File
+	line    int
+	entry   *Entry
+	keyword string  // Keyword of the string being read
+	value   *string // String being read
+	strings bool    // Whether the entry has keywords already
+}
+
+func (p *parser) parseLine(line string) error {
+	obsolete := false
+	switch {
+	case line == "":
+		p.end()
+		return nil
+	case strings.HasPrefix(line, "#~") && !strings.HasPrefix(line, "#~|"):
+		obsolete = true
+		line = strings.TrimSpace(line[2:])
+		if line == "" {
+			return nil
+		}
+	case strings.HasPrefix(line, "#"):
+		if p.strings {
+			p.end()
+		}
+		e := p.current()
+		if strings.HasPrefix(line, "#,") {
+			for _, flag := range strings.Split(line[2:], ",") {
+				if flag = strings.TrimSpace(flag); flag != "" {
+					e.AddFlag(flag)
+				}
+			}
+		} else {
+			e.Comments = append(e.Comments, line)
+		}
+		return nil
+	}
+
+	if strings.HasPrefix(line, `"`) {
+		if p.value == nil {
+			return fmt.Errorf("string without keyword")
+		}
+		s, err := unquote(line)
+		if err != nil {
+			return err
+		}
+		*p.value += s
+		return nil
+	}
+
+	keyword, rest, _ := strings.Cut(line, " ")
+	s, err := unquote(strings.TrimSpace(rest))
+	if err != nil {
+		return err
+	}
+	if p.strings && (keyword == "msgctxt" || keyword == "msgid" && p.keyword != "msgctxt") {
+		p.end()
+	}
+	e := p.current()
+	e.Obsolete = e.Obsolete || obsolete
+	p.strings = true
+	p.keyword = keyword
+
+	switch {
+	case keyword == "msgctxt":
+		e.Context, p.value = s, &e.Context
+	case keyword == "msgid":
+		e.ID, p.value = s, &e.ID
+	case keyword == "msgid_plural":
+		e.IDPlural, p.value = s, &e.IDPlural
+	case keyword == "msgstr":
+		e.Str, p.value = s, &e.Str
+	case strings.HasPrefix(keyword, "msgstr[") && strings.HasSuffix(keyword, "]"):
+		n, err := strconv.Atoi(keyword[len("msgstr[") : len(keyword)-1])
+		if err != nil || n != len(e.StrPlural) {
+			return fmt.Errorf("unexpected %s", keyword)
+		}
+		e.StrPlural = append(e.StrPlural, s)
+		p.value = &e.StrPlural[n]
+	default:
+		return fmt.Errorf("unknown keyword %q", keyword)
+	}
+	return nil
+}
+
+// current returns the entry being read, starting one if needed.
+func (p *parser) current() *Entry {
+	if p.entry == nil {
+		p.entry = &Entry{}
+	}
+	return p.entry
+}
+
+// end ends the entry being read.
+func (p *parser) end() {
+	if p.entry != nil {
+		p.file.Entries = append(p.file.Entries, p.entry)
+	}
+	p.entry, p.keyword, p.value, p.strings = nil, "", nil, false
+}
+
+// unquote unquotes a PO string.
+func unquote(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", fmt.Errorf("invalid string %s", s)
+	}
+	s = s[1 : len(s)-1]
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case 'a':
+			b.WriteByte('\a')
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'v':
+			b.WriteByte('\v')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
+
+// quote quotes s as a PO string.
+func quote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`, "\a", `\a`, "\b", `\b`, "\f", `\f`, "\v", `\v`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// WriteTo writes f in the PO format.
+func (f *File) WriteTo(w io.Writer) (int64, error) {
+	var b bytes.Buffer
+	for i, e := range f.Entries {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		e.write(&b)
+	}
+	return b.WriteTo(w)
+}
+
+// Bytes returns f in the PO format.
+func (f *File) Bytes() []byte {
+	var b bytes.Buffer
+	f.WriteTo(&b)
+	return b.Bytes()
+}
+
+// write writes e in the PO format: comments, flags, previous messages, then
+// strings, wrapped the way gettext tools wrap them.
+func (e *Entry) write(b *bytes.Buffer) {
+	for _, c := range e.Comments {
+		if !strings.HasPrefix(c, "#|") {
+			b.WriteString(c + "\n")
+		}
+	}
+	if len(e.Flags) > 0 {
+		b.WriteString("#, " + strings.Join(e.Flags, ", ") + "\n")
+	}
+	for _, c := range e.Comments {
+		if strings.HasPrefix(c, "#|") {
+			b.WriteString(c + "\n")
+		}
+	}
+
+	prefix := ""
+	if e.Obsolete {
+		prefix = "#~ "
+	}
+	if e.Context != "" {
+		writeString(b, prefix, "msgctxt", e.Context)
+	}
+	writeString(b, prefix, "msgid", e.ID)
+	if e.IDPlural == "" {
+		writeString(b, prefix, "msgstr", e.Str)
+		return
+	}
+	writeString(b, prefix, "msgid_plural", e.IDPlural)
+	for n, s := range e.StrPlural {
+		writeString(b, prefix, "msgstr["+strconv.Itoa(n)+"]", s)
+	}
+}
+
+// writeString writes the string s of keyword. Strings holding line breaks
+// or too long for a line are written on several lines, starting with an
+// empty one.
+func writeString(b *bytes.Buffer, prefix, keyword, s string) {
+	line := prefix + keyword + " " + quote(s)
+	if len(line) <= lineWidth && !strings.Contains(strings.TrimSuffix(s, "\n"), "\n") {
+		b.WriteString(line + "\n")
+		return
+	}
+	b.WriteString(prefix + keyword + ` ""` + "\n")
+	for _, part := range strings.SplitAfter(s, "\n") {
+		if part == "" {
+			continue
+		}
+		for _, piece := range wrap(quote(part), lineWidth-len(prefix)) {
+			b.WriteString(prefix + piece + "\n")
+		}
+	}
+}
+
+// wrap splits the quoted string s into quoted strings of at most width
+// characters, breaking after spaces when possible.
+func wrap(s string, width int) []string {
+	body := s[1 : len(s)-1]
+	var lines []string
+	for len(body)+2 > width {
+		cut := strings.LastIndexByte(body[:width-2], ' ')
+		if cut <= 0 {
+			break
+		}
+		lines = append(lines, `"`+body[:cut+1]+`"`)
+		body = body[cut+1:]
+	}
+	return append(lines, `"`+body+`"`)
+}
@@ -0,0 +1,104 @@
+package gettext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const catalog = `# Translations of the app.
+#
+msgid ""
+msgstr ""
+"Project-Id-Version: app 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+
+#. Shown on the home page
+#: src/main.c:12 src/main.c:40
+#, c-format
+msgid "Hello, %s!"
+msgstr ""
+
+#: src/main.c:20
+msgctxt "menu"
+msgid "Open"
+msgstr "Buka"
+
+#, fuzzy
+#| msgid "One file"
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] ""
+msgstr[1] ""
+
+msgid ""
+"A long text that is longer than a single line of a catalog, so that it is "
+"wrapped by the writer.\n"
+"It says \"hi\"\tthere.\n"
+msgstr ""
+
+#~ msgid "Removed"
+#~ msgstr "Dihapus"
+`
+
+func TestParse(t *testing.T) {
+	f, err := Parse([]byte(catalog))
+	require.NoError(t, err)
+	require.Len(t, f.Entries, 6)
+
+	header := f.Header()
+	require.NotNil(t, header)
+	assert.Equal(t, []string{"# Translations of the app.", "#"}, header.Comments)
+	assert.Equal(t, "app 1.0", f.HeaderField("project-id-version"))
+
+	hello := f.Entries[1]
+	assert.Equal(t, []string{"#. Shown on the home page", "#: src/main.c:12 src/main.c:40"}, hello.Comments)
+	assert.Equal(t, []string{"c-format"}, hello.Flags)
+	assert.Equal(t, "Hello, %s!", hello.ID)
+	assert.False(t, hello.Translated())
+
+	open := f.Entries[2]
+	assert.Equal(t, "menu", open.Context)
+	assert.Equal(t, "Buka", open.Str)
+	assert.True(t, open.Translated())
+
+	files := f.Entries[3]
+	assert.True(t, files.HasFlag(Fuzzy))
+	assert.Equal(t, []string{"#| msgid \"One file\""}, files.Comments)
+	assert.Equal(t, "%d files", files.IDPlural)
+	assert.Equal(t, []string{"", ""}, files.StrPlural)
+
+	assert.Equal(t, "A long text that is longer than a single line of a catalog, so that it is wrapped by the writer.\nIt says \"hi\"\tthere.\n", f.Entries[4].ID)
+
+	removed := f.Entries[5]
+	assert.True(t, removed.Obsolete)
+	assert.Equal(t, "Dihapus", removed.Str)
+}
+
+func TestWrite(t *testing.T) {
+	f, err := Parse([]byte(catalog))
+	require.NoError(t, err)
+	assert.Equal(t, catalog, string(f.Bytes()))
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, data := range []string{
+		`"orphan"`,
+		`msgid "unterminated`,
+		`msgfoo "x"`,
+		"msgid \"a\"\nmsgid_plural \"b\"\nmsgstr[1] \"c\"",
+	} {
+		_, err := Parse([]byte(data))
+		assert.Error(t, err, data)
+	}
+}
+
+func TestSetHeaderField(t *testing.T) {
+	f := &File{}
+	f.SetHeaderField("Language", "id")
+	f.SetHeaderField("MIME-Version", "1.0")
+	f.SetHeaderField("language", "fr")
+	assert.Equal(t, "language: fr\nMIME-Version: 1.0\n", f.Header().Str)
+	assert.Equal(t, "fr", f.HeaderField("Language"))
+}
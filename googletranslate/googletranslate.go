@@ -3,10 +3,14 @@ package googletranslate
 import (
 	"net/http"
 	"sync"
+
+	"gopkg.gilang.dev/translator/v2/ratelimit"
 )
 
 const (
-	DefaultHost = "google.com"
+	DefaultHost     = "google.com"
+	BackendName     = "google"
+	DefaultMaxChars = 5000
 )
 
 // GoogleTranslate is a concurrency-safe client for the Google Translate API.
@@ -15,6 +19,8 @@ type GoogleTranslate struct {
 	host     string
 	client   *http.Client
 	proxyURL string
+	limiter  *ratelimit.Limiter
+	maxChars int
 }
 
 // Option is a functional option for configuring GoogleTranslate.
@@ -41,11 +47,27 @@ func WithProxyURL(proxyURL string) Option {
 	}
 }
 
+// WithRateLimiter sets a limiter shared by all requests of the client.
+func WithRateLimiter(limiter *ratelimit.Limiter) Option {
+	return func(gt *GoogleTranslate) {
+		gt.limiter = limiter
+	}
+}
+
+// WithMaxChars sets the most characters sent in a single request. Longer
+// texts are split by the gt package. Zero disables splitting.
+func WithMaxChars(n int) Option {
+	return func(gt *GoogleTranslate) {
+		gt.maxChars = n
+	}
+}
+
 // New creates a new GoogleTranslate client with the given options.
 func New(opts ...Option) *GoogleTranslate {
 	gt := &GoogleTranslate{
-		host:   DefaultHost,
-		client: &http.Client{},
+		host:     DefaultHost,
+		client:   &http.Client{},
+		maxChars: DefaultMaxChars,
 	}
 	for _, opt := range opts {
 		opt(gt)
@@ -94,3 +116,31 @@ func (gt *GoogleTranslate) SetProxyURL(proxyURL string) {
 	defer gt.mu.Unlock()
 	gt.proxyURL = proxyURL
 }
+
+// RateLimiter returns the current rate limiter.
+func (gt *GoogleTranslate) RateLimiter() *ratelimit.Limiter {
+	gt.mu.RLock()
+	defer gt.mu.RUnlock()
+	return gt.limiter
+}
+
+// SetRateLimiter sets the rate limiter.
+func (gt *GoogleTranslate) SetRateLimiter(limiter *ratelimit.Limiter) {
+	gt.mu.Lock()
+	defer gt.mu.Unlock()
+	gt.limiter = limiter
+}
+
+// MaxChars returns the most characters sent in a single request.
+func (gt *GoogleTranslate) MaxChars() int {
+	gt.mu.RLock()
+	defer gt.mu.RUnlock()
+	return gt.maxChars
+}
+
+// SetMaxChars sets the most characters sent in a single request.
+func (gt *GoogleTranslate) SetMaxChars(n int) {
+	gt.mu.Lock()
+	defer gt.mu.Unlock()
+	gt.maxChars = n
+}
@@ -5,6 +5,9 @@ import (
 	"net/http"
 	"testing"
 	"time"
+
+	"github.com/tidwall/gjson"
+	"gopkg.gilang.dev/translator/v2/ratelimit"
 )
 
 func TestNew(t *testing.T) {
@@ -70,3 +73,34 @@ func TestTranslate(t *testing.T) {
 		t.Error("Translation returned empty text")
 	}
 }
+
+func TestWithRateLimiter(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.WithRequestsPerSecond(1, 1))
+	gt := New(WithRateLimiter(limiter))
+	if gt.RateLimiter() != limiter {
+		t.Error("Rate limiter not set correctly")
+	}
+	gt.SetRateLimiter(nil)
+	if gt.RateLimiter() != nil {
+		t.Error("Rate limiter not cleared")
+	}
+}
+
+func TestParseTranslated(t *testing.T) {
+	data := gjson.Parse(`[[null,null,"en"],[[[null,null,null,null,null,[["Halo"],["dunia"]]]],null,null,"en"]]`)
+	result := parseTranslated(data)
+	if result.Text != "Halo dunia" {
+		t.Errorf("Expected %q, got %q", "Halo dunia", result.Text)
+	}
+	if result.From.Language.Iso != "en" {
+		t.Errorf("Expected detected language en, got %q", result.From.Language.Iso)
+	}
+}
+
+func TestParseTranslatedLineBreaks(t *testing.T) {
+	data := gjson.Parse(`[[null,null,"en"],[[[null,null,null,null,null,[["Halo dunia.\n"],["Apa kabar?"],[" Baik."]]]],null,null,"en"]]`)
+	result := parseTranslated(data)
+	if result.Text != "Halo dunia.\nApa kabar? Baik." {
+		t.Errorf("Expected %q, got %q", "Halo dunia.\nApa kabar? Baik.", result.Text)
+	}
+}
@@ -0,0 +1,57 @@
+package googletranslate
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+	"gopkg.gilang.dev/translator/v2/errs"
+)
+
+// supported holds the codes of languages.
+var supported = func() map[string]bool {
+	codes := make(map[string]bool, len(languages))
+	for _, lang := range languages {
+		codes[lang.Code] = true
+	}
+	return codes
+}()
+
+// LanguageCode converts a BCP 47 language tag into the code Google Translate
+// expects, such as "zh-TW" for "zh-Hant", "iw" for "he" or "jw" for "jv".
+// "auto" is returned unchanged. Languages Google doesn't support are reported
+// with errs.ErrUnsupportedPair.
+func LanguageCode(tag string) (string, error) {
+	if tag == "auto" {
+		return tag, nil
+	}
+	parsed, err := language.Parse(tag)
+	if err != nil {
+		return "", errs.New(errs.ErrInvalidLanguage, BackendName, 0, fmt.Sprintf("language %q isn't valid", tag), err)
+	}
+
+	base, _ := parsed.Base()
+	code := base.String()
+	switch code {
+	case "zh":
+		if script, _ := parsed.Script(); script.String() == "Hant" {
+			code = "zh-TW"
+		} else {
+			code = "zh-CN"
+		}
+	case "he":
+		code = "iw"
+	case "jv":
+		code = "jw"
+	case "fil":
+		code = "tl"
+	case "nb", "nn":
+		code = "no"
+	case "mni":
+		code = "mni-Mtei"
+	}
+
+	if !supported[code] {
+		return "", errs.New(errs.ErrUnsupportedPair, BackendName, 0, fmt.Sprintf("language %q isn't supported", tag), nil)
+	}
+	return code, nil
+}
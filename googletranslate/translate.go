@@ -9,9 +9,11 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/imroc/req/v3"
 	"github.com/tidwall/gjson"
+	"gopkg.gilang.dev/translator/v2/errs"
 )
 
 // check retrieves session data from Google Translate page.
@@ -45,11 +47,13 @@ func (gt *GoogleTranslate) check(ctx context.Context) (*reqData, error) {
 
 	resp, err := r.Get(baseURL)
 	if err != nil {
-		return nil, fmt.Errorf("error: bad network")
+		return nil, errs.New(errs.ErrNetwork, BackendName, 0, "bad network", err)
 	}
 
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("error: request failed with status code %d", resp.StatusCode)
+		e := errs.FromStatus(BackendName, resp.StatusCode, "")
+		e.RetryAfter = errs.ParseRetryAfter(resp.Header.Get("Retry-After"))
+		return nil, e
 	}
 
 	body := resp.String()
@@ -63,17 +67,45 @@ func (gt *GoogleTranslate) check(ctx context.Context) (*reqData, error) {
 
 // Translate translates text from one language to another.
 func (gt *GoogleTranslate) Translate(ctx context.Context, text string, from string, to string) (*Translated, error) {
+	data, err := gt.request(ctx, text, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return parseTranslated(data), nil
+}
+
+// Detect returns the ISO code of the language of text, as detected by Google
+// while translating it to English.
+func (gt *GoogleTranslate) Detect(ctx context.Context, text string) (string, error) {
+	data, err := gt.request(ctx, text, "auto", "en")
+	if err != nil {
+		return "", err
+	}
+	iso := data.Get("1.3").String()
+	if iso == "" {
+		return "", errs.New(errs.ErrUnexpectedResponse, BackendName, http.StatusOK, "no detected language in response", nil)
+	}
+	return iso, nil
+}
+
+// request sends a translation request and returns the parsed inner JSON response.
+func (gt *GoogleTranslate) request(ctx context.Context, text string, from string, to string) (gjson.Result, error) {
 	gt.mu.RLock()
 	host := gt.host
 	proxyURL := gt.proxyURL
+	limiter := gt.limiter
 	gt.mu.RUnlock()
 
 	rpcId := "MkEWBc"
 	baseURL := "https://translate." + host
 
+	if err := limiter.Wait(ctx, utf8.RuneCountInString(text)); err != nil {
+		return gjson.Result{}, err
+	}
+
 	checkData, err := gt.check(ctx)
 	if err != nil {
-		return nil, err
+		return gjson.Result{}, err
 	}
 
 	// Build query parameters
@@ -123,36 +155,50 @@ func (gt *GoogleTranslate) Translate(ctx context.Context, text string, from stri
 
 	resp, err := r.SetBody(bytes.NewBufferString(body.Encode())).Post(fullURL)
 	if err != nil {
-		return nil, fmt.Errorf("error: bad network")
+		return gjson.Result{}, errs.New(errs.ErrNetwork, BackendName, 0, "bad network", err)
+	}
+
+	if resp.StatusCode != 200 {
+		e := errs.FromStatus(BackendName, resp.StatusCode, "")
+		e.RetryAfter = errs.ParseRetryAfter(resp.Header.Get("Retry-After"))
+		return gjson.Result{}, e
 	}
 
 	raw := resp.String()
 	if len(raw) < 6 {
-		return nil, fmt.Errorf("error: invalid response")
+		return gjson.Result{}, errs.New(errs.ErrUnexpectedResponse, BackendName, resp.StatusCode, "invalid response", nil)
 	}
 
 	// Parse response
 	lines := strings.Split(raw[6:], "\n")
 	if len(lines) < 2 {
-		return nil, fmt.Errorf("error: parsing response")
+		return gjson.Result{}, errs.New(errs.ErrUnexpectedResponse, BackendName, resp.StatusCode, "parsing response", nil)
 	}
 
 	// Parse first level JSON
 	result := gjson.Parse(lines[1])
 	innerJSON := result.Get("0.2").String()
 	if innerJSON == "" {
-		return nil, fmt.Errorf("error: request on google translate api isn't working, please check your parameter")
+		return gjson.Result{}, errs.New(errs.ErrUnexpectedResponse, BackendName, resp.StatusCode, "request on google translate api isn't working, please check your parameter", nil)
 	}
 
 	// Parse inner JSON
-	data := gjson.Parse(innerJSON)
+	return gjson.Parse(innerJSON), nil
+}
 
-	// Extract translation result
+// parseTranslated extracts a translation result from the inner JSON response.
+func parseTranslated(data gjson.Result) *Translated {
+	// Extract translation result. Sentences are separated by a space
+	// unless they already end or start with whitespace, such as the line
+	// breaks of the text, which are kept.
 	var textBuilder strings.Builder
 	sentences := data.Get("1.0.0.5").Array()
-	for _, sentence := range sentences {
-		textBuilder.WriteString(sentence.Get("0").String())
-		textBuilder.WriteString(" ")
+	for i, sentence := range sentences {
+		text := sentence.Get("0").String()
+		if i > 0 && needsSpace(textBuilder.String(), text) {
+			textBuilder.WriteString(" ")
+		}
+		textBuilder.WriteString(text)
 	}
 	translatedText := strings.TrimSpace(textBuilder.String())
 
@@ -201,5 +247,5 @@ func (gt *GoogleTranslate) Translate(ctx context.Context, text string, from stri
 				DidYouMean:    didYouMean,
 			},
 		},
-	}, nil
+	}
 }
@@ -3,6 +3,8 @@ package googletranslate
 import (
 	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 // extract extracts a value from a string using regex.
@@ -18,3 +20,11 @@ func extract(key string, value string) string {
 	replace := strings.ReplaceAll(res, `"`+key+`":"`, "")
 	return replace[:len(replace)-1]
 }
+
+// needsSpace tells whether a space separates the sentences before and after,
+// that is, whether there is no whitespace between them already.
+func needsSpace(before, after string) bool {
+	last, _ := utf8.DecodeLastRuneInString(before)
+	first, _ := utf8.DecodeRuneInString(after)
+	return before != "" && after != "" && !unicode.IsSpace(last) && !unicode.IsSpace(first)
+}
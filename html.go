@@ -0,0 +1,324 @@
+package gt
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"gopkg.gilang.dev/translator/v2/internal/placeholder"
+	"gopkg.gilang.dev/translator/v2/params"
+)
+
+// translatableAttributes are the attributes holding human-readable text.
+var translatableAttributes = map[string]bool{
+	"alt":         true,
+	"title":       true,
+	"placeholder": true,
+}
+
+// untranslatedElements are the elements whose content is never translated.
+var untranslatedElements = map[atom.Atom]bool{
+	atom.Script: true,
+	atom.Style:  true,
+	atom.Code:   true,
+}
+
+// TranslateHTML translates the text of an HTML document or fragment with the
+// default translator, keeping its markup. See TranslateHTMLWith.
+func TranslateHTML(ctx context.Context, document, from, to string) (*Translated, error) {
+	return TranslateHTMLWith(ctx, getTranslator(), document, from, to)
+}
+
+// TranslateHTMLWith translates the text of an HTML document or fragment using
+// a specific translator. Only text and the alt, title and placeholder
+// attributes are translated; <script>, <style> and <code> elements and
+// elements marked translate="no" or class="notranslate" are left as is.
+// The inline content of each block, such as a paragraph, is translated as a
+// whole, with its inline elements (<a>, <b>, <span>, …) protected by
+// placeholders, so that sentences split by them read right; a block whose
+// inline elements the backend lost or reordered keeps its source. Unless the
+// request in ctx sets one, TagHandling is "html" for the backends supporting
+// it. The result holds the reassembled HTML; a fragment stays a fragment.
+func TranslateHTMLWith(ctx context.Context, translator Translator, document, from, to string) (*Translated, error) {
+	if strings.TrimSpace(document) == "" {
+		return nil, errTextRequired()
+	}
+	if from == "" {
+		from = "auto"
+	}
+
+	root, err := parseHTML(document)
+	if err != nil {
+		return nil, err
+	}
+	segments := collectHTMLSegments(root, true, nil)
+
+	result := &Translated{}
+	if len(segments) > 0 {
+		// Identical texts, such as repeated labels, are translated once
+		var (
+			texts   []string
+			indexes = make(map[string]int)
+		)
+		for _, s := range segments {
+			if _, ok := indexes[s.body]; !ok {
+				indexes[s.body] = len(texts)
+				texts = append(texts, s.body)
+			}
+		}
+
+		if req, _ := RequestFromContext(ctx); req.TagHandling == "" {
+			req.TagHandling = params.TagHandlingHTML
+			ctx = ContextWithRequest(ctx, req)
+		}
+		results, err := batchWith(ctx, translator, texts, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range results {
+			if r.Err != nil {
+				return nil, r.Err
+			}
+		}
+		*result = *results[0].Translated
+		result.Alternatives = nil
+		result.Pronunciation = nil
+
+		for _, s := range segments {
+			text := results[indexes[s.body]].Translated.Text
+			if !placeholder.Intact(text, s.tokens, s.pairs) {
+				continue // Keep the source rather than misplaced markup
+			}
+			s.set(s.lead + text + s.trail)
+		}
+	}
+
+	var b strings.Builder
+	if err := html.Render(&b, root); err != nil {
+		return nil, err
+	}
+	result.Text = b.String()
+	return result, nil
+}
+
+// htmlSegment is a translatable text of a document: an attribute, or the
+// inline content of a block with placeholders for its inline elements.
+type htmlSegment struct {
+	textSpan
+	tokens int                // Number of placeholders
+	pairs  []placeholder.Pair // Placeholders of start and end tags
+	set    func(string)
+}
+
+// parseHTML parses a full document when it starts with a doctype or an <html>
+// element, or a fragment of a <body> otherwise, which it returns as the
+// children of a document node.
+func parseHTML(document string) (*html.Node, error) {
+	start := strings.ToLower(strings.TrimSpace(document))
+	if strings.HasPrefix(start, "<!doctype") || strings.HasPrefix(start, "<html") {
+		return html.Parse(strings.NewReader(document))
+	}
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(document), body)
+	if err != nil {
+		return nil, err
+	}
+	root := &html.Node{Type: html.DocumentNode}
+	for _, n := range nodes {
+		root.AppendChild(n)
+	}
+	return root, nil
+}
+
+// collectHTMLSegments appends the translatable texts of the children of n,
+// and of their descendants, to segments. translate tells whether the content
+// of n is translatable. Runs of text and inline elements make one segment.
+func collectHTMLSegments(n *html.Node, translate bool, segments []*htmlSegment) []*htmlSegment {
+	var run []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if translate && (c.Type == html.TextNode || c.Type == html.CommentNode || isInline(c)) {
+			run = append(run, c)
+			continue
+		}
+		segments = appendHTMLRun(segments, run)
+		run = nil
+		segments = collectHTMLElement(c, translate, segments)
+	}
+	segments = appendHTMLRun(segments, run)
+	return segments
+}
+
+// collectHTMLElement appends the translatable attributes of n, if an element,
+// and the translatable texts of its content to segments.
+func collectHTMLElement(n *html.Node, translate bool, segments []*htmlSegment) []*htmlSegment {
+	if n.Type != html.ElementNode {
+		return segments
+	}
+	translate = elementTranslatable(n, translate)
+	if translate {
+		segments = collectHTMLAttributes(n, segments)
+	}
+	if untranslatedElements[n.DataAtom] {
+		return segments
+	}
+	return collectHTMLSegments(n, translate, segments)
+}
+
+// collectHTMLAttributes appends the translatable attributes of n to segments.
+func collectHTMLAttributes(n *html.Node, segments []*htmlSegment) []*htmlSegment {
+	for i := range n.Attr {
+		attr := &n.Attr[i]
+		if attr.Namespace == "" && translatableAttributes[strings.ToLower(attr.Key)] {
+			segments = appendHTMLSegment(segments, &htmlSegment{textSpan: splitSpace(attr.Val), set: func(s string) { attr.Val = s }})
+		}
+	}
+	return segments
+}
+
+// inlineElements are the elements laid out within a line of text.
+var inlineElements = map[atom.Atom]bool{
+	atom.A: true, atom.Abbr: true, atom.B: true, atom.Bdi: true, atom.Bdo: true,
+	atom.Br: true, atom.Cite: true, atom.Code: true, atom.Data: true, atom.Del: true,
+	atom.Dfn: true, atom.Em: true, atom.I: true, atom.Img: true, atom.Ins: true,
+	atom.Kbd: true, atom.Mark: true, atom.Q: true, atom.S: true, atom.Samp: true,
+	atom.Small: true, atom.Span: true, atom.Strong: true, atom.Sub: true, atom.Sup: true,
+	atom.Time: true, atom.U: true, atom.Var: true, atom.Wbr: true,
+}
+
+// isInline tells whether n is an inline element holding only text and
+// inline elements.
+func isInline(n *html.Node) bool {
+	if n.Type != html.ElementNode || !inlineElements[n.DataAtom] {
+		return false
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && !isInline(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// appendHTMLRun appends to segments the run of sibling text and inline
+// nodes, as a text where the start and end tags of translatable inline
+// elements and the whole of the others are placeholders. Translating it
+// rebuilds the nodes of the run from the translation, reusing the elements.
+func appendHTMLRun(segments []*htmlSegment, run []*html.Node) []*htmlSegment {
+	if len(run) == 0 {
+		return segments
+	}
+	var (
+		b      strings.Builder
+		items  []*html.Node // Node of each placeholder
+		pairs  []placeholder.Pair
+		opens  = make(map[int]bool) // Placeholders of start tags
+		closes = make(map[int]bool) // Placeholders of end tags
+	)
+	protect := func(n *html.Node) {
+		b.WriteString(placeholder.Token(len(items)))
+		items = append(items, n)
+	}
+	var add func(n *html.Node)
+	add = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			b.WriteString(n.Data)
+		case n.Type == html.ElementNode && n.FirstChild != nil && !untranslatedElements[n.DataAtom] && elementTranslatable(n, true):
+			// The element is kept around its translated content
+			segments = collectHTMLAttributes(n, segments)
+			open := len(items)
+			opens[open] = true
+			protect(n)
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				add(c)
+			}
+			pairs = append(pairs, placeholder.Pair{Open: open, Close: len(items)})
+			closes[len(items)] = true
+			protect(n)
+		default:
+			// The node is kept whole, though its attributes or descendants
+			// may still be translatable
+			segments = collectHTMLElement(n, true, segments)
+			protect(n)
+		}
+	}
+	for _, n := range run {
+		add(n)
+	}
+
+	parent, next := run[0].Parent, run[len(run)-1].NextSibling
+	set := func(text string) {
+		for _, n := range run {
+			parent.RemoveChild(n)
+		}
+		for i, n := range items {
+			if closes[i] { // The content is rebuilt
+				for n.FirstChild != nil {
+					n.RemoveChild(n.FirstChild)
+				}
+			}
+		}
+		stack := []*html.Node{parent}
+		insert := func(n *html.Node) {
+			if top := stack[len(stack)-1]; top != parent {
+				top.AppendChild(n)
+			} else {
+				parent.InsertBefore(n, next)
+			}
+		}
+		last := 0
+		for _, match := range placeholder.Pattern.FindAllStringSubmatchIndex(text, -1) {
+			if text[last:match[0]] != "" {
+				insert(&html.Node{Type: html.TextNode, Data: text[last:match[0]]})
+			}
+			last = match[1]
+			i, _ := strconv.Atoi(text[match[2]:match[3]])
+			switch {
+			case closes[i]:
+				stack = stack[:len(stack)-1]
+			case opens[i]:
+				insert(items[i])
+				stack = append(stack, items[i])
+			default:
+				insert(items[i])
+			}
+		}
+		if text[last:] != "" {
+			insert(&html.Node{Type: html.TextNode, Data: text[last:]})
+		}
+	}
+	return appendHTMLSegment(segments, &htmlSegment{textSpan: splitSpace(b.String()), tokens: len(items), pairs: pairs, set: set})
+}
+
+// elementTranslatable tells whether the content of n is translatable, given
+// whether its parent content is, following the translate attribute.
+func elementTranslatable(n *html.Node, inherited bool) bool {
+	for _, attr := range n.Attr {
+		switch strings.ToLower(attr.Key) {
+		case "translate":
+			switch strings.ToLower(strings.TrimSpace(attr.Val)) {
+			case "no":
+				return false
+			case "", "yes":
+				inherited = true
+			}
+		case "class":
+			for _, class := range strings.Fields(attr.Val) {
+				if class == "notranslate" {
+					return false
+				}
+			}
+		}
+	}
+	return inherited
+}
+
+// appendHTMLSegment appends s to segments unless it has nothing to translate.
+func appendHTMLSegment(segments []*htmlSegment, s *htmlSegment) []*htmlSegment {
+	if placeholder.Empty(s.body) {
+		return segments
+	}
+	return append(segments, s)
+}
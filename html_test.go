@@ -0,0 +1,115 @@
+package gt
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.gilang.dev/translator/v2/params"
+)
+
+func TestTranslateHTMLFragment(t *testing.T) {
+	stub := &stubTranslator{}
+	document := `<p class="intro">Hello <b>world</b> &amp; friends!</p>
+<img src="cat.png" alt="A cat" title="Cat">
+<input placeholder="Your name" value="keep">
+<script>var greeting = "hello";</script>
+<style>p { color: red; }</style>
+<p>Run <code>go test</code> now.</p>
+<div translate="no">Brand <span translate="yes">slogan</span></div>
+<span class="x notranslate">Product</span>
+<p>Hello <b>world</b> &amp; friends!</p>`
+
+	result, err := TranslateHTMLWith(context.Background(), stub, document, "en", "id")
+	require.NoError(t, err)
+	assert.Equal(t, `<p class="intro">HELLO <b>WORLD</b> &amp; FRIENDS!</p>
+<img src="cat.png" alt="A CAT" title="CAT"/>
+<input placeholder="YOUR NAME" value="keep"/>
+<script>var greeting = "hello";</script>
+<style>p { color: red; }</style>
+<p>RUN <code>go test</code> NOW.</p>
+<div translate="no">Brand <span translate="yes">SLOGAN</span></div>
+<span class="x notranslate">Product</span>
+<p>HELLO <b>WORLD</b> &amp; FRIENDS!</p>`, result.Text)
+
+	// The first paragraph appears twice but is translated once
+	assert.Equal(t, int32(6), stub.calls)
+}
+
+func TestTranslateHTMLDocument(t *testing.T) {
+	stub := &stubTranslator{}
+	document := "<!DOCTYPE html><html><head><title>Home</title></head><body><h1>Welcome</h1></body></html>"
+
+	result, err := TranslateHTMLWith(context.Background(), stub, document, "", "id")
+	require.NoError(t, err)
+	assert.Equal(t, "<!DOCTYPE html><html><head><title>HOME</title></head><body><h1>WELCOME</h1></body></html>", result.Text)
+}
+
+func TestTranslateHTMLInline(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		texts []string
+		seen  TranslateRequest
+	)
+	translator := funcTranslator(func(ctx context.Context, text, from, to string) (*Translated, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		texts = append(texts, text)
+		seen, _ = RequestFromContext(ctx)
+		// Moves the link to the end of the sentence, as a German word order would
+		return &Translated{Text: strings.NewReplacer("Click ⟦0⟧here⟦1⟧ to continue", "Um fortzufahren, ⟦0⟧hier⟦1⟧ klicken", "Docs", "Doku").Replace(text)}, nil
+	})
+	document := `<div><p>Click <a href="/next" title="Docs">here</a> to continue</p></div>`
+
+	result, err := TranslateHTMLWith(context.Background(), translator, document, "en", "de")
+	require.NoError(t, err)
+	assert.Equal(t, `<div><p>Um fortzufahren, <a href="/next" title="Doku">hier</a> klicken</p></div>`, result.Text)
+	assert.ElementsMatch(t, []string{"Click ⟦0⟧here⟦1⟧ to continue", "Docs"}, texts)
+	assert.Equal(t, params.TagHandlingHTML, seen.TagHandling)
+
+	// Nested inline elements, and inline elements kept whole
+	stub := &stubTranslator{}
+	result, err = TranslateHTMLWith(context.Background(), stub, `<li>Use <em>the <b>new</b></em><br>tool, <code>go vet</code></li>`, "en", "id")
+	require.NoError(t, err)
+	assert.Equal(t, `<li>USE <em>THE <b>NEW</b></em><br/>TOOL, <code>go vet</code></li>`, result.Text)
+	assert.Equal(t, int32(1), stub.calls)
+}
+
+func TestTranslateHTMLBrokenInline(t *testing.T) {
+	for name, rewrite := range map[string]func(string) string{
+		"dropped": func(text string) string { return strings.Replace(text, "⟦1⟧", "", 1) },
+		"swapped": strings.NewReplacer("⟦0⟧", "⟦1⟧", "⟦1⟧", "⟦0⟧").Replace,
+	} {
+		t.Run(name, func(t *testing.T) {
+			stub := &stubTranslator{fn: func(text, from, to string) (*Translated, error) {
+				return &Translated{Text: strings.ToUpper(rewrite(text))}, nil
+			}}
+			result, err := TranslateHTMLWith(context.Background(), stub, `<p>Click <a href="/next">here</a> to continue</p><p>Bye</p>`, "en", "id")
+			require.NoError(t, err)
+			assert.Equal(t, `<p>Click <a href="/next">here</a> to continue</p><p>BYE</p>`, result.Text)
+		})
+	}
+}
+
+func TestTranslateHTMLErrors(t *testing.T) {
+	_, err := TranslateHTMLWith(context.Background(), &stubTranslator{}, " ", "en", "id")
+	assert.ErrorIs(t, err, ErrEmptyText)
+
+	failing := &stubTranslator{fn: func(text, from, to string) (*Translated, error) {
+		if strings.Contains(text, "bad") {
+			return nil, ErrUnexpectedResponse
+		}
+		return &Translated{Text: text}, nil
+	}}
+	_, err = TranslateHTMLWith(context.Background(), failing, "<p>good</p><p>bad</p>", "en", "id")
+	assert.ErrorIs(t, err, ErrUnexpectedResponse)
+
+	stub := &stubTranslator{}
+	result, err := TranslateHTMLWith(context.Background(), stub, "<script>x()</script>", "en", "id")
+	require.NoError(t, err)
+	assert.Equal(t, "<script>x()</script>", result.Text)
+	assert.Zero(t, stub.calls)
+}
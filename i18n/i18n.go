@@ -0,0 +1,362 @@
+// Package i18n translates JSON resource files, such as i18next, go-i18n or
+// flat key/value files, keeping their key structure, key order and
+// interpolations intact.
+package i18n
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	gt "gopkg.gilang.dev/translator/v2"
+	"gopkg.gilang.dev/translator/v2/internal/placeholder"
+)
+
+// Interpolation matches the interpolations and markup left untranslated by
+// default: i18next {{value}}, $t(key) nesting and <1>…</1> tags, go-i18n
+// {{.Value}} templates, ICU {value} arguments and printf format specifiers.
+var Interpolation = []*regexp.Regexp{
+	regexp.MustCompile(`\{\{[^{}]*\}\}|\$t\([^()]*\)|\{[A-Za-z_][\w.]*\}|</?[A-Za-z0-9][\w-]*\s*/?>`),
+	placeholder.Printf,
+}
+
+// messageKeys are the keys of a go-i18n message object, and pluralForms the
+// ones holding translatable texts.
+var (
+	messageKeys = map[string]bool{"id": true, "description": true, "hash": true, "leftdelim": true, "rightdelim": true}
+	pluralForms = map[string]bool{"zero": true, "one": true, "two": true, "few": true, "many": true, "other": true}
+)
+
+// Translator translates JSON resource files with a gt.Translator.
+type Translator struct {
+	translator    gt.Translator
+	placeholders  []*regexp.Regexp
+	indent        string
+	indentDefined bool
+}
+
+// Option is a functional option for configuring New.
+type Option func(*Translator)
+
+// WithPlaceholders replaces the patterns of the texts left untranslated,
+// Interpolation by default.
+func WithPlaceholders(patterns ...*regexp.Regexp) Option {
+	return func(t *Translator) {
+		t.placeholders = patterns
+	}
+}
+
+// WithIndent sets the indentation of the written files, "" for compact
+// files. By default, the indentation of the source file is kept, and compact
+// files stay compact.
+func WithIndent(indent string) Option {
+	return func(t *Translator) {
+		t.indent = indent
+		t.indentDefined = true
+	}
+}
+
+// New creates a Translator of JSON resource files using translator.
+func New(translator gt.Translator, opts ...Option) *Translator {
+	t := &Translator{
+		translator:   translator,
+		placeholders: Interpolation,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Translate translates every string value of the JSON resource file data,
+// whatever its depth, and returns the file with the same keys in the same
+// order. Interpolations are kept as is, as are numbers, booleans and the
+// id, description and hash of go-i18n messages. All texts are translated in
+// a single batch.
+func (t *Translator) Translate(ctx context.Context, data []byte, from, to string) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	root, err := decode(dec)
+	if err == nil {
+		if _, err = dec.Token(); err == io.EOF {
+			err = nil
+		} else if err == nil {
+			err = errors.New("unexpected data after top-level value")
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("i18n: %w", err)
+	}
+
+	var segments []*segment
+	collect(root, t.placeholders, &segments)
+	if err := translateSegments(ctx, t.translator, segments, from, to); err != nil {
+		return nil, err
+	}
+
+	indent, compact := t.indent, t.indent == ""
+	if !t.indentDefined {
+		indent, compact = detectIndent(data)
+	}
+	var b bytes.Buffer
+	if err := encode(&b, root, indent, compact, ""); err != nil {
+		return nil, fmt.Errorf("i18n: %w", err)
+	}
+	if bytes.HasSuffix(bytes.TrimRight(data, " \t\r"), []byte("\n")) {
+		b.WriteByte('\n')
+	}
+	return b.Bytes(), nil
+}
+
+// object is a JSON object keeping the order of its keys.
+type object struct {
+	keys   []string
+	values []any
+}
+
+// segment is a translatable string value.
+type segment struct {
+	lead, text, trail string
+	tokens            []string
+	set               func(string)
+}
+
+// decode decodes the next JSON value of dec. Objects are decoded to *object,
+// arrays to []any and other values to their token.
+func decode(dec *json.Decoder) (any, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case json.Delim('{'):
+		o := &object{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decode(dec)
+			if err != nil {
+				return nil, err
+			}
+			o.keys = append(o.keys, key.(string))
+			o.values = append(o.values, value)
+		}
+		_, err := dec.Token()
+		return o, err
+	case json.Delim('['):
+		items := []any{}
+		for dec.More() {
+			item, err := decode(dec)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		_, err := dec.Token()
+		return items, err
+	}
+	return token, nil
+}
+
+// collect appends the translatable strings of v to segments. The only texts
+// of go-i18n messages are their plural forms, or their translation in the
+// v1 format.
+func collect(v any, placeholders []*regexp.Regexp, segments *[]*segment) {
+	switch v := v.(type) {
+	case *object:
+		if i := translationIndex(v); i >= 0 {
+			if s, ok := v.values[i].(string); ok {
+				appendSegment(segments, s, placeholders, func(s string) { v.values[i] = s })
+			} else {
+				collect(v.values[i], placeholders, segments)
+			}
+			return
+		}
+		message := isMessage(v)
+		for i, key := range v.keys {
+			if message && !pluralForms[strings.ToLower(key)] {
+				continue
+			}
+			if s, ok := v.values[i].(string); ok {
+				appendSegment(segments, s, placeholders, func(s string) { v.values[i] = s })
+				continue
+			}
+			collect(v.values[i], placeholders, segments)
+		}
+	case []any:
+		for i, item := range v {
+			if s, ok := item.(string); ok {
+				appendSegment(segments, s, placeholders, func(s string) { v[i] = s })
+				continue
+			}
+			collect(item, placeholders, segments)
+		}
+	}
+}
+
+// isMessage tells whether o is a go-i18n message: an object of strings with
+// an "other" plural form and no keys but those of messages.
+func isMessage(o *object) bool {
+	other := false
+	for i, key := range o.keys {
+		key = strings.ToLower(key)
+		if _, ok := o.values[i].(string); !ok || !messageKeys[key] && !pluralForms[key] {
+			return false
+		}
+		other = other || key == "other"
+	}
+	return other
+}
+
+// translationIndex returns the index of the "translation" key of o if o is a
+// go-i18n v1 message, such as {"id": "hello", "translation": "Hello"}, whose
+// translation is a string or an object of plural forms, or -1 otherwise.
+func translationIndex(o *object) int {
+	id, translation := false, -1
+	for i, key := range o.keys {
+		switch strings.ToLower(key) {
+		case "id":
+			_, id = o.values[i].(string)
+		case "translation":
+			translation = i
+		}
+	}
+	if !id || translation < 0 {
+		return -1
+	}
+	switch value := o.values[translation].(type) {
+	case string:
+		return translation
+	case *object:
+		for i, key := range value.keys {
+			if _, ok := value.values[i].(string); !ok || !pluralForms[strings.ToLower(key)] {
+				return -1
+			}
+		}
+		return translation
+	}
+	return -1
+}
+
+// appendSegment appends s to segments unless it has nothing to translate.
+func appendSegment(segments *[]*segment, s string, placeholders []*regexp.Regexp, set func(string)) {
+	text, tokens := placeholder.Protect(strings.TrimSpace(s), placeholders...)
+	if placeholder.Empty(text) {
+		return
+	}
+	start := strings.Index(s, strings.TrimSpace(s))
+	end := start + len(strings.TrimSpace(s))
+	*segments = append(*segments, &segment{lead: s[:start], text: text, trail: s[end:], tokens: tokens, set: set})
+}
+
+// translateSegments translates segments in a single batch, translating
+// identical texts once.
+func translateSegments(ctx context.Context, translator gt.Translator, segments []*segment, from, to string) error {
+	if len(segments) == 0 {
+		return nil
+	}
+	var (
+		texts   []string
+		indexes = make(map[string]int)
+	)
+	for _, s := range segments {
+		if _, ok := indexes[s.text]; !ok {
+			indexes[s.text] = len(texts)
+			texts = append(texts, s.text)
+		}
+	}
+
+	results, err := gt.TranslateBatchWith(ctx, translator, texts, from, to)
+	if err != nil {
+		return err
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			return r.Err
+		}
+	}
+	for _, s := range segments {
+		s.set(s.lead + placeholder.Restore(results[indexes[s.text]].Translated.Text, s.tokens) + s.trail)
+	}
+	return nil
+}
+
+// detectIndent returns the indentation of the first indented line of data,
+// and whether data is compact, holding no line break.
+func detectIndent(data []byte) (string, bool) {
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) < 2 {
+		return "", true
+	}
+	for _, line := range lines[1:] {
+		if indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]; indent != "" {
+			return indent, false
+		}
+	}
+	return "  ", false
+}
+
+// encode writes v to b, indenting nested values with indent after prefix
+// unless compact.
+func encode(b *bytes.Buffer, v any, indent string, compact bool, prefix string) error {
+	var open, close byte
+	var n int
+	switch v := v.(type) {
+	case *object:
+		open, close, n = '{', '}', len(v.keys)
+	case []any:
+		open, close, n = '[', ']', len(v)
+	default:
+		return encodeScalar(b, v)
+	}
+
+	b.WriteByte(open)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		if !compact {
+			b.WriteString("\n" + prefix + indent)
+		}
+		value := v
+		if o, ok := v.(*object); ok {
+			if err := encodeScalar(b, o.keys[i]); err != nil {
+				return err
+			}
+			b.WriteByte(':')
+			if !compact {
+				b.WriteByte(' ')
+			}
+			value = o.values[i]
+		} else {
+			value = v.([]any)[i]
+		}
+		if err := encode(b, value, indent, compact, prefix+indent); err != nil {
+			return err
+		}
+	}
+	if n > 0 && !compact {
+		b.WriteString("\n" + prefix)
+	}
+	b.WriteByte(close)
+	return nil
+}
+
+// encodeScalar writes a string, number, boolean or null to b, leaving HTML
+// characters unescaped.
+func encodeScalar(b *bytes.Buffer, v any) error {
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	b.Truncate(b.Len() - 1) // Encode's newline
+	return nil
+}
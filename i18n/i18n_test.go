@@ -0,0 +1,152 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.gilang.dev/translator/v2/internal/translatortest"
+)
+
+func TestTranslateNested(t *testing.T) {
+	source := `{
+    "welcome": "Welcome, {{name}}!",
+    "nav": {
+        "home": "Home",
+        "items_one": "{{count}} item",
+        "items_other": "{{count}} items",
+        "empty": "",
+        "only": "{{count}}"
+    },
+    "help": "See $t(nav.home) or <1>the docs</1> & more",
+    "list": ["First", 2, true, null, {"z": "Last"}],
+    "size": 1.50,
+    "again": "Home",
+    "spaced": " Home "
+}
+`
+	want := `{
+    "welcome": "WELCOME, {{name}}!",
+    "nav": {
+        "home": "HOME",
+        "items_one": "{{count}} ITEM",
+        "items_other": "{{count}} ITEMS",
+        "empty": "",
+        "only": "{{count}}"
+    },
+    "help": "SEE $t(nav.home) OR <1>THE DOCS</1> & MORE",
+    "list": ["FIRST", 2, true, null, {"z": "LAST"}],
+    "size": 1.50,
+    "again": "HOME",
+    "spaced": " HOME "
+}
+`
+	u := &translatortest.Upper{}
+	got, err := New(u).Translate(context.Background(), []byte(source), "en", "id")
+	require.NoError(t, err)
+
+	// Arrays are written one item per line
+	want = strings.Replace(want, `["FIRST", 2, true, null, {"z": "LAST"}]`, `[
+        "FIRST",
+        2,
+        true,
+        null,
+        {
+            "z": "LAST"
+        }
+    ]`, 1)
+	assert.Equal(t, want, string(got))
+	assert.Len(t, u.Texts(), 7, "identical texts are translated once")
+	assert.Contains(t, u.Texts(), "Welcome, ⟦0⟧!")
+}
+
+func TestTranslateFlat(t *testing.T) {
+	u := &translatortest.Upper{}
+	got, err := New(u).Translate(context.Background(), []byte(`{"a.b":"Hello %s","c":"100% done"}`), "en", "id")
+	require.NoError(t, err)
+	assert.Equal(t, `{"a.b":"HELLO %s","c":"100% DONE"}`, string(got))
+
+	got, err = New(u, WithIndent("\t")).Translate(context.Background(), []byte(`{"a":"b"}`), "en", "id")
+	require.NoError(t, err)
+	assert.Equal(t, "{\n\t\"a\": \"B\"\n}", string(got))
+}
+
+func TestTranslateGoI18nMessages(t *testing.T) {
+	source := `{
+  "PersonCats": {
+    "description": "The number of cats a person has",
+    "hash": "sha1-abc",
+    "one": "{{.Name}} has {{.Count}} cat.",
+    "other": "{{.Name}} has {{.Count}} cats."
+  },
+  "Other": {
+    "description": "Not a message without other"
+  }
+}`
+	want := `{
+  "PersonCats": {
+    "description": "The number of cats a person has",
+    "hash": "sha1-abc",
+    "one": "{{.Name}} HAS {{.Count}} CAT.",
+    "other": "{{.Name}} HAS {{.Count}} CATS."
+  },
+  "Other": {
+    "description": "NOT A MESSAGE WITHOUT OTHER"
+  }
+}`
+	got, err := New(&translatortest.Upper{}).Translate(context.Background(), []byte(source), "en", "id")
+	require.NoError(t, err)
+	assert.Equal(t, want, string(got))
+}
+
+func TestTranslateGoI18nV1(t *testing.T) {
+	source := `[
+  {
+    "id": "greeting",
+    "translation": "Hello {{.Name}}"
+  },
+  {
+    "id": "person_cats",
+    "translation": {
+      "one": "{{.Count}} cat",
+      "other": "{{.Count}} cats"
+    }
+  }
+]`
+	want := `[
+  {
+    "id": "greeting",
+    "translation": "HELLO {{.Name}}"
+  },
+  {
+    "id": "person_cats",
+    "translation": {
+      "one": "{{.Count}} CAT",
+      "other": "{{.Count}} CATS"
+    }
+  }
+]`
+	got, err := New(&translatortest.Upper{}).Translate(context.Background(), []byte(source), "en", "id")
+	require.NoError(t, err)
+	assert.Equal(t, want, string(got))
+}
+
+func TestTranslateErrors(t *testing.T) {
+	u := &translatortest.Upper{}
+	for _, source := range []string{``, `{"a":`, `{"a":"b"} {}`} {
+		_, err := New(u).Translate(context.Background(), []byte(source), "en", "id")
+		assert.Error(t, err, source)
+	}
+	assert.Empty(t, u.Texts())
+
+	got, err := New(u).Translate(context.Background(), []byte(`{"a":{}, "b":[]}`), "en", "id")
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":{},"b":[]}`, string(got))
+
+	u.Err = errors.New("backend down")
+	_, err = New(u).Translate(context.Background(), []byte(`{"a":"b"}`), "en", "id")
+	assert.ErrorIs(t, err, u.Err)
+}
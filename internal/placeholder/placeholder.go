@@ -0,0 +1,152 @@
+// Package placeholder protects the parts of a text that must survive
+// translation unchanged, such as format specifiers or markup, by replacing
+// them with numbered tokens that backends leave alone.
+package placeholder
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Pattern matches the tokens of Protect, tolerating spaces a backend may add
+// around the number.
+var Pattern = regexp.MustCompile(`⟦\s*(\d+)\s*⟧`)
+
+// Printf matches C, Objective-C, Java and Python format specifiers, such as
+// %s, %1$s, %@, %lld, %.2f, %(name)s and %%. Space flags are not supported,
+// so that "100% sure" isn't taken for one.
+var Printf = regexp.MustCompile(`%(?:\(\w+\)|\d+\$)?[-+#0']*(?:\d+|\*)?(?:\.(?:\d+|\*))?(?:hh|h|ll|l|L|q|j|z|t)?[diouxXeEfFgGaAcspn@%]`)
+
+// Token returns the token of the nth protected text.
+func Token(n int) string {
+	return fmt.Sprintf("⟦%d⟧", n)
+}
+
+// Protect replaces every match of the patterns in text with a token and
+// returns the texts they replace. Where matches overlap, the first pattern
+// matching at the leftmost position wins.
+func Protect(text string, patterns ...*regexp.Regexp) (string, []string) {
+	var (
+		b      strings.Builder
+		tokens []string
+	)
+	for len(text) > 0 {
+		start, end := -1, -1
+		for _, pattern := range patterns {
+			if loc := pattern.FindStringIndex(text); loc != nil && loc[1] > loc[0] && (start < 0 || loc[0] < start) {
+				start, end = loc[0], loc[1]
+			}
+		}
+		if start < 0 {
+			break
+		}
+		b.WriteString(text[:start])
+		b.WriteString(Token(len(tokens)))
+		tokens = append(tokens, text[start:end])
+		text = text[end:]
+	}
+	b.WriteString(text)
+	return b.String(), tokens
+}
+
+// Restore replaces the tokens of Protect in text with the texts they
+// protect. Protected texts whose token was dropped by the backend are
+// appended, so that none is lost.
+func Restore(text string, tokens []string) string {
+	used := make([]bool, len(tokens))
+	text = Pattern.ReplaceAllStringFunc(text, func(token string) string {
+		n, err := strconv.Atoi(Pattern.FindStringSubmatch(token)[1])
+		if err != nil || n >= len(tokens) {
+			return token
+		}
+		used[n] = true
+		return tokens[n]
+	})
+	for n, token := range tokens {
+		if !used[n] {
+			text += " " + token
+		}
+	}
+	return text
+}
+
+// Empty tells whether text holds nothing but tokens and whitespace, and so
+// has nothing to translate.
+func Empty(text string) bool {
+	return strings.TrimSpace(Pattern.ReplaceAllString(text, "")) == ""
+}
+
+// Pair is a token opening a span of text, such as a start tag, and the token
+// closing it.
+type Pair struct{ Open, Close int }
+
+// tag matches a start or end tag, capturing the slash of end tags, the name,
+// and the slash of self-closing tags.
+var tag = regexp.MustCompile(`^<(/?)([A-Za-z][\w:.-]*)(?:\s[^<>]*?)?(/?)>$`)
+
+// TagPairs pairs the start and end tags among texts, such as those of
+// Protect, by name. Other texts, self-closing tags and unclosed tags, such as
+// <br>, are left out.
+func TagPairs(texts []string) []Pair {
+	var (
+		pairs []Pair
+		open  []int // Start tags not yet closed, innermost last
+	)
+	for i, text := range texts {
+		m := tag.FindStringSubmatch(text)
+		switch {
+		case m == nil || m[3] != "":
+		case m[1] == "":
+			open = append(open, i)
+		default:
+			for j := len(open) - 1; j >= 0; j-- {
+				if strings.EqualFold(tag.FindStringSubmatch(texts[open[j]])[2], m[2]) {
+					pairs = append(pairs, Pair{Open: open[j], Close: i})
+					open = open[:j]
+					break
+				}
+			}
+		}
+	}
+	return pairs
+}
+
+// Intact tells whether text holds each of n tokens exactly once and no other
+// token, with the tokens of each pair in order and the pairs nested as in the
+// protected text. Restore can then put back markup, such as tags, that must
+// stay balanced.
+func Intact(text string, n int, pairs []Pair) bool {
+	seen := make([]bool, n)
+	closes := make(map[int]int, len(pairs)) // Opening token to its closing one
+	opens := make(map[int]bool, len(pairs))
+	for _, p := range pairs {
+		closes[p.Open] = p.Close
+		opens[p.Close] = true
+	}
+	var stack []int // Closing tokens expected, innermost last
+	for _, m := range Pattern.FindAllStringSubmatch(text, -1) {
+		i, err := strconv.Atoi(m[1])
+		if err != nil || i >= n || seen[i] {
+			return false
+		}
+		seen[i] = true
+		if c, ok := closes[i]; ok {
+			stack = append(stack, c)
+			continue
+		}
+		if opens[i] {
+			if len(stack) == 0 || stack[len(stack)-1] != i {
+				return false
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	for _, ok := range seen {
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+package placeholder
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProtect(t *testing.T) {
+	braces := regexp.MustCompile(`\{\{[^}]*\}\}`)
+	text, tokens := Protect("Hi {{name}}, you have %d new %s and %1$@ (100% sure, %%)", braces, Printf)
+	assert.Equal(t, "Hi ⟦0⟧, you have ⟦1⟧ new ⟦2⟧ and ⟦3⟧ (100% sure, ⟦4⟧)", text)
+	assert.Equal(t, []string{"{{name}}", "%d", "%s", "%1$@", "%%"}, tokens)
+
+	text, tokens = Protect("%(count)d files, %.2f%% done, %lld bytes", Printf)
+	assert.Equal(t, "⟦0⟧ files, ⟦1⟧⟦2⟧ done, ⟦3⟧ bytes", text)
+	assert.Equal(t, []string{"%(count)d", "%.2f", "%%", "%lld"}, tokens)
+
+	text, tokens = Protect("Nothing here", Printf)
+	assert.Equal(t, "Nothing here", text)
+	assert.Empty(t, tokens)
+}
+
+func TestRestore(t *testing.T) {
+	tokens := []string{"{{name}}", "%d"}
+	assert.Equal(t, "Halo {{name}}, %d pesan", Restore("Halo ⟦ 0 ⟧, ⟦1 ⟧ pesan", tokens))
+	assert.Equal(t, "Halo ⟦7⟧ {{name}} %d", Restore("Halo ⟦7⟧", tokens))
+}
+
+func TestEmpty(t *testing.T) {
+	assert.True(t, Empty(" ⟦0⟧ ⟦1⟧ "))
+	assert.False(t, Empty("⟦0⟧ items"))
+}
+
+func TestIntact(t *testing.T) {
+	pairs := []Pair{{0, 2}, {1, 3}}
+	assert.True(t, Intact("⟦0⟧a ⟦1⟧b⟦3⟧ c⟦2⟧ ⟦4⟧", 5, pairs))
+	assert.True(t, Intact("⟦4⟧ ⟦ 0 ⟧⟦2⟧ ⟦1⟧⟦3⟧", 5, pairs))
+	assert.False(t, Intact("⟦0⟧a ⟦1⟧b⟦3⟧ c⟦2⟧", 5, pairs), "dropped")
+	assert.False(t, Intact("⟦0⟧a ⟦1⟧b⟦3⟧ c⟦2⟧ ⟦4⟧ ⟦4⟧", 5, pairs), "repeated")
+	assert.False(t, Intact("⟦0⟧a ⟦1⟧b⟦3⟧ c⟦2⟧ ⟦4⟧ ⟦5⟧", 5, pairs), "unknown")
+	assert.False(t, Intact("⟦2⟧a ⟦1⟧b⟦3⟧ c⟦0⟧ ⟦4⟧", 5, pairs), "swapped")
+	assert.False(t, Intact("⟦0⟧a ⟦1⟧b⟦2⟧ c⟦3⟧ ⟦4⟧", 5, pairs), "crossed")
+}
+
+func TestTagPairs(t *testing.T) {
+	tokens := []string{`<a href="x">`, "<br>", "<b>", "%d", "<img/>", "</B>", "</a>", "</i>"}
+	assert.Equal(t, []Pair{{2, 5}, {0, 6}}, TagPairs(tokens))
+}
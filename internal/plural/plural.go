@@ -0,0 +1,94 @@
+// Package plural gives the CLDR plural categories of languages, which
+// Android plurals and Apple stringsdict files use.
+package plural
+
+import "strings"
+
+// Categories of plural forms, in CLDR order.
+const (
+	Zero  = "zero"
+	One   = "one"
+	Two   = "two"
+	Few   = "few"
+	Many  = "many"
+	Other = "other"
+)
+
+// categories holds the cardinal plural categories of common languages.
+var categories = map[string][]string{
+	"ar": {Zero, One, Two, Few, Many, Other},
+	"be": {One, Few, Many, Other},
+	"bg": {One, Other},
+	"bn": {One, Other},
+	"bs": {One, Few, Other},
+	"ca": {One, Many, Other},
+	"cs": {One, Few, Many, Other},
+	"cy": {Zero, One, Two, Few, Many, Other},
+	"da": {One, Other},
+	"de": {One, Other},
+	"el": {One, Other},
+	"en": {One, Other},
+	"es": {One, Many, Other},
+	"et": {One, Other},
+	"fa": {One, Other},
+	"fi": {One, Other},
+	"fr": {One, Many, Other},
+	"ga": {One, Two, Few, Many, Other},
+	"he": {One, Two, Other},
+	"hi": {One, Other},
+	"hr": {One, Few, Other},
+	"hu": {One, Other},
+	"id": {Other},
+	"it": {One, Many, Other},
+	"ja": {Other},
+	"km": {Other},
+	"ko": {Other},
+	"lo": {Other},
+	"lt": {One, Few, Many, Other},
+	"lv": {Zero, One, Other},
+	"ms": {Other},
+	"my": {Other},
+	"nb": {One, Other},
+	"nl": {One, Other},
+	"no": {One, Other},
+	"pl": {One, Few, Many, Other},
+	"pt": {One, Many, Other},
+	"ro": {One, Few, Other},
+	"ru": {One, Few, Many, Other},
+	"sk": {One, Few, Many, Other},
+	"sl": {One, Two, Few, Other},
+	"sr": {One, Few, Other},
+	"sv": {One, Other},
+	"sw": {One, Other},
+	"th": {Other},
+	"tr": {One, Other},
+	"uk": {One, Few, Many, Other},
+	"ur": {One, Other},
+	"vi": {Other},
+	"zh": {Other},
+}
+
+// Categories returns the plural categories of language, a code such as
+// "fr", "pt-BR" or "zh_Hant", or nil if it is unknown.
+func Categories(language string) []string {
+	base, _, _ := strings.Cut(strings.ReplaceAll(language, "_", "-"), "-")
+	return categories[strings.ToLower(base)]
+}
+
+// Adapt returns the forms of the language of categories, given the forms of
+// another language: each category gets the form of the same category, or
+// the "other" form. It returns forms as is when categories is nil.
+func Adapt(forms map[string]string, categories []string) map[string]string {
+	if categories == nil {
+		return forms
+	}
+	adapted := make(map[string]string, len(categories))
+	for _, category := range categories {
+		form, ok := forms[category]
+		if !ok {
+			form = forms[Other]
+		}
+		adapted[category] = form
+	}
+	return adapted
+}
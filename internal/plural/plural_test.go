@@ -0,0 +1,22 @@
+package plural
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategories(t *testing.T) {
+	assert.Equal(t, []string{One, Few, Many, Other}, Categories("ru"))
+	assert.Equal(t, []string{One, Many, Other}, Categories("pt-BR"))
+	assert.Equal(t, []string{Other}, Categories("zh_Hant"))
+	assert.Equal(t, []string{One, Other}, Categories("EN"))
+	assert.Nil(t, Categories("xx"))
+}
+
+func TestAdapt(t *testing.T) {
+	forms := map[string]string{One: "%d file", Other: "%d files"}
+	assert.Equal(t, map[string]string{One: "%d file", Few: "%d files", Many: "%d files", Other: "%d files"}, Adapt(forms, Categories("ru")))
+	assert.Equal(t, map[string]string{Other: "%d files"}, Adapt(forms, Categories("ja")))
+	assert.Equal(t, forms, Adapt(forms, nil))
+}
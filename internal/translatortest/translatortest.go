@@ -0,0 +1,50 @@
+// Package translatortest provides a gt.Translator for the tests of the
+// packages translating documents, so that they run offline.
+package translatortest
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	gt "gopkg.gilang.dev/translator/v2"
+)
+
+// Upper is a gt.Translator upper-casing texts and recording the texts and
+// languages it is called with. It is safe for concurrent use.
+type Upper struct {
+	Err     error                    // Returned by every call when set
+	Rewrite func(text string) string // Applied to texts before upper-casing, when set
+
+	mu       sync.Mutex
+	texts    []string
+	from, to string
+}
+
+func (u *Upper) Translate(ctx context.Context, text, from, to string) (*gt.Translated, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.texts = append(u.texts, text)
+	u.from, u.to = from, to
+	if u.Err != nil {
+		return nil, u.Err
+	}
+	if u.Rewrite != nil {
+		text = u.Rewrite(text)
+	}
+	return &gt.Translated{Text: strings.ToUpper(text), Backend: "upper"}, nil
+}
+
+// Texts returns the texts translated so far, in call order.
+func (u *Upper) Texts() []string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return append([]string(nil), u.texts...)
+}
+
+// Languages returns the languages of the last call.
+func (u *Upper) Languages() (from, to string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.from, u.to
+}
@@ -0,0 +1,38 @@
+package gt
+
+import (
+	"fmt"
+
+	"gopkg.gilang.dev/translator/v2/deepl"
+	"gopkg.gilang.dev/translator/v2/googletranslate"
+)
+
+// LanguageCode converts a BCP 47 language tag into the code expected by a
+// backend, as source language or, if target is true, as target language.
+// For example "he" becomes "iw" for Google, and "pt" becomes "PT-BR" as a
+// DeepL target. The adapters of this package apply it to every request.
+func LanguageCode(backend TranslatorType, tag string, target bool) (string, error) {
+	switch backend {
+	case Google:
+		return googletranslate.LanguageCode(tag)
+	case DeepL:
+		if target {
+			return deepl.TargetLanguageCode(tag)
+		}
+		return deepl.SourceLanguageCode(tag)
+	}
+	return "", fmt.Errorf("unknown translator type %q", backend)
+}
+
+// languagePair converts from and to with LanguageCode.
+func languagePair(backend TranslatorType, from, to string) (string, string, error) {
+	from, err := LanguageCode(backend, from, false)
+	if err != nil {
+		return "", "", err
+	}
+	to, err = LanguageCode(backend, to, true)
+	if err != nil {
+		return "", "", err
+	}
+	return from, to, nil
+}
@@ -0,0 +1,57 @@
+package gt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLanguageCode(t *testing.T) {
+	tests := []struct {
+		backend TranslatorType
+		tag     string
+		target  bool
+		want    string
+	}{
+		{Google, "zh-CN", true, "zh-CN"},
+		{Google, "zh-Hant", true, "zh-TW"},
+		{Google, "he", true, "iw"},
+		{Google, "jv", false, "jw"},
+		{Google, "en-GB", true, "en"},
+		{Google, "auto", false, "auto"},
+		{DeepL, "zh-CN", true, "ZH-HANS"},
+		{DeepL, "zh-TW", true, "ZH-HANT"},
+		{DeepL, "zh-CN", false, "ZH"},
+		{DeepL, "pt", true, "PT-BR"},
+		{DeepL, "pt-PT", true, "PT-PT"},
+		{DeepL, "en", true, "EN-US"},
+		{DeepL, "en-AU", true, "EN-GB"},
+		{DeepL, "en-GB", false, "EN"},
+		{DeepL, "es-MX", true, "ES-419"},
+		{DeepL, "es", true, "ES"},
+		{DeepL, "iw", false, "HE"},
+		{DeepL, "no", true, "NB"},
+	}
+	for _, tt := range tests {
+		got, err := LanguageCode(tt.backend, tt.tag, tt.target)
+		assert.NoError(t, err, tt.tag)
+		assert.Equal(t, tt.want, got, "%s %s target=%v", tt.backend, tt.tag, tt.target)
+	}
+}
+
+func TestLanguageCodeErrors(t *testing.T) {
+	_, err := LanguageCode(DeepL, "jv", true)
+	assert.ErrorIs(t, err, ErrUnsupportedPair)
+
+	_, err = LanguageCode(Google, "not a language", true)
+	assert.ErrorIs(t, err, ErrInvalidLanguage)
+
+	_, err = LanguageCode("other", "en", true)
+	assert.Error(t, err)
+}
+
+func TestAdapterRejectsUnsupportedLanguage(t *testing.T) {
+	_, err := NewDeepLTranslator().Translate(context.Background(), "Hello", "en", "jv")
+	assert.ErrorIs(t, err, ErrUnsupportedPair)
+}
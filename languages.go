@@ -0,0 +1,77 @@
+package gt
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Language describes a language accepted by a backend.
+type Language struct {
+	Code              string   `json:"code"` // Code to pass to the backend
+	Name              string   `json:"name"`
+	Source            bool     `json:"source"`                       // Accepted as source language
+	Target            bool     `json:"target"`                       // Accepted as target language
+	SupportsFormality bool     `json:"supports_formality,omitempty"` // Honors TranslateRequest.Formality as target
+	Variants          []string `json:"variants,omitempty"`           // Regional variants accepted as target
+}
+
+// LanguageLister is implemented by translators that can list the languages they accept.
+type LanguageLister interface {
+	Languages(ctx context.Context) ([]Language, error)
+}
+
+// Languages implements LanguageLister.
+func (g *googleTranslateAdapter) Languages(ctx context.Context) ([]Language, error) {
+	langs, err := g.client.Languages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Language, len(langs))
+	for i, lang := range langs {
+		result[i] = Language{
+			Code:   lang.Code,
+			Name:   lang.Name,
+			Source: lang.Source,
+			Target: lang.Target,
+		}
+	}
+	return result, nil
+}
+
+// Languages implements LanguageLister.
+func (d *deeplAdapter) Languages(ctx context.Context) ([]Language, error) {
+	langs, err := d.client.Languages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Language, len(langs))
+	for i, lang := range langs {
+		result[i] = Language{
+			Code:              strings.ToLower(lang.Code),
+			Name:              lang.Name,
+			Source:            lang.Source,
+			Target:            lang.Target,
+			SupportsFormality: lang.SupportsFormality,
+		}
+		for _, variant := range lang.Variants {
+			result[i].Variants = append(result[i].Variants, strings.ToLower(variant))
+		}
+	}
+	return result, nil
+}
+
+// Languages returns the languages accepted by the default translator.
+func Languages(ctx context.Context) ([]Language, error) {
+	translator := getTranslator()
+	lister, ok := translator.(LanguageLister)
+	if !ok {
+		return nil, fmt.Errorf("translator %T can't list its languages", translator)
+	}
+	return LanguagesWith(ctx, lister)
+}
+
+// LanguagesWith returns the languages accepted by a specific translator.
+func LanguagesWith(ctx context.Context, lister LanguageLister) ([]Language, error) {
+	return lister.Languages(ctx)
+}
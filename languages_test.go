@@ -0,0 +1,42 @@
+package gt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoogleLanguages(t *testing.T) {
+	langs, err := LanguagesWith(context.Background(), NewGoogleTranslator().(LanguageLister))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, langs)
+
+	codes := map[string]Language{}
+	for _, lang := range langs {
+		assert.True(t, lang.Source && lang.Target, lang.Code)
+		codes[lang.Code] = lang
+	}
+	assert.Contains(t, codes, "zh-CN")
+	assert.Contains(t, codes, "iw")
+	assert.NotContains(t, codes, "mo")
+}
+
+func TestDeepLLanguages(t *testing.T) {
+	langs, err := LanguagesWith(context.Background(), NewDeepLTranslator().(LanguageLister))
+	assert.NoError(t, err)
+
+	codes := map[string]Language{}
+	for _, lang := range langs {
+		codes[lang.Code] = lang
+	}
+	assert.True(t, codes["de"].SupportsFormality)
+	assert.False(t, codes["ko"].SupportsFormality)
+	assert.Equal(t, []string{"pt-br", "pt-pt"}, codes["pt"].Variants)
+	assert.NotContains(t, codes, "jv")
+
+	// Returned slices are copies
+	langs[0].Name = "changed"
+	again, _ := LanguagesWith(context.Background(), NewDeepLTranslator().(LanguageLister))
+	assert.NotEqual(t, "changed", again[0].Name)
+}
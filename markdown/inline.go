@@ -0,0 +1,188 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"gopkg.gilang.dev/translator/v2/internal/placeholder"
+)
+
+var (
+	autolink   = regexp.MustCompile(`^<(?:[A-Za-z][A-Za-z0-9+.-]{1,31}:[^\s<>]*|[^\s<>@]+@[^\s<>@]+)>`)
+	inlineHTML = regexp.MustCompile(`^<(?:/?[A-Za-z][A-Za-z0-9-]*(?:\s[^<>]*)?/?|!--.*?--)>`)
+	bareURL    = regexp.MustCompile(`^(?:https?://|www\.)[^\s<>]*[^\s<>.,;:!?'")\]*_]`)
+	footnote   = regexp.MustCompile(`^\[\^[^\]]+\]`)
+)
+
+// protectInline replaces the markup of text that must not be translated with
+// numbered placeholders: code spans, autolinks, inline HTML, bare URLs,
+// footnote references, escapes, and the brackets and targets of links and
+// images, whose texts stay translatable. It returns the markup of each
+// placeholder, and the placeholders opening and closing link texts and HTML
+// elements.
+func protectInline(text string) (string, []string, []placeholder.Pair) {
+	var (
+		b      strings.Builder
+		tokens []string
+		pairs  []placeholder.Pair
+		closes = make(map[int]int) // Closing bracket of a link label to the end of its target
+		opens  = make(map[int]int) // Closing bracket of a link label to the placeholder of its opening one
+	)
+	protect := func(s string) {
+		b.WriteString(placeholder.Token(len(tokens)))
+		tokens = append(tokens, s)
+	}
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		if end, ok := closes[i]; ok {
+			pairs = append(pairs, placeholder.Pair{Open: opens[i], Close: len(tokens)})
+			protect(text[i:end])
+			i = end
+			continue
+		}
+
+		switch c := text[i]; {
+		case c == '\\' && i+1 < len(text) && isASCIIPunct(text[i+1]):
+			protect(text[i : i+2])
+			i += 2
+			continue
+		case c == '`':
+			if end := codeSpanEnd(text, i); end > 0 {
+				protect(text[i:end])
+				i = end
+				continue
+			}
+			n := len(rest) - len(strings.TrimLeft(rest, "`"))
+			b.WriteString(rest[:n])
+			i += n
+			continue
+		case c == '<':
+			if m := autolink.FindString(rest); m != "" {
+				protect(m)
+				i += len(m)
+				continue
+			}
+			if m := inlineHTML.FindString(rest); m != "" {
+				protect(m)
+				i += len(m)
+				continue
+			}
+		case c == '[' || c == '!' && strings.HasPrefix(rest, "!["):
+			if m := footnote.FindString(rest); m != "" {
+				protect(m)
+				i += len(m)
+				continue
+			}
+			open := i
+			if c == '!' {
+				open++
+			}
+			if label, end := linkEnd(text, open); end > 0 {
+				opens[label] = len(tokens)
+				protect(text[i : open+1])
+				closes[label] = end
+				i = open + 1
+				continue
+			}
+		case (c == 'h' || c == 'w') && (i == 0 || !isWordChar(rune(text[i-1]))):
+			if m := bareURL.FindString(rest); m != "" {
+				protect(m)
+				i += len(m)
+				continue
+			}
+		}
+		b.WriteByte(text[i])
+		i++
+	}
+	return b.String(), tokens, append(pairs, placeholder.TagPairs(tokens)...)
+}
+
+// codeSpanEnd returns the end of the code span starting at i, or 0 if the
+// backticks there aren't closed.
+func codeSpanEnd(text string, i int) int {
+	n := len(text[i:]) - len(strings.TrimLeft(text[i:], "`"))
+	for j := i + n; j < len(text); {
+		k := strings.IndexByte(text[j:], '`')
+		if k < 0 {
+			return 0
+		}
+		j += k
+		m := len(text[j:]) - len(strings.TrimLeft(text[j:], "`"))
+		if m == n {
+			return j + m
+		}
+		j += m
+	}
+	return 0
+}
+
+// linkEnd returns, for the link label opening at text[open], the index of its
+// closing bracket and the end of the target or reference following it. It
+// returns 0 for both when the label isn't followed by one.
+func linkEnd(text string, open int) (int, int) {
+	depth := 0
+	for j := open; j < len(text); j++ {
+		switch text[j] {
+		case '\\':
+			j++
+		case '`':
+			if end := codeSpanEnd(text, j); end > 0 {
+				j = end - 1
+			}
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth > 0 {
+				continue
+			}
+			if end := targetEnd(text, j+1); end > 0 {
+				return j, end
+			}
+			return 0, 0
+		}
+	}
+	return 0, 0
+}
+
+// targetEnd returns the end of the (target) or [reference] starting at
+// text[i], or 0 if there is none.
+func targetEnd(text string, i int) int {
+	if i >= len(text) {
+		return 0
+	}
+	var open, close byte
+	switch text[i] {
+	case '(':
+		open, close = '(', ')'
+	case '[':
+		open, close = '[', ']'
+	default:
+		return 0
+	}
+	depth := 0
+	for j := i; j < len(text); j++ {
+		switch text[j] {
+		case '\\':
+			j++
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return j + 1
+			}
+		}
+	}
+	return 0
+}
+
+func isASCIIPunct(c byte) bool {
+	return c < unicode.MaxASCII && unicode.IsPunct(rune(c)) || strings.IndexByte("$+<=>^`|~", c) >= 0
+}
+
+func isWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+}
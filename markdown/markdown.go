@@ -0,0 +1,482 @@
+// Package markdown translates Markdown documents while keeping their code,
+// link targets, front matter keys, tables and heading anchors intact.
+package markdown
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	gt "gopkg.gilang.dev/translator/v2"
+	"gopkg.gilang.dev/translator/v2/errs"
+	"gopkg.gilang.dev/translator/v2/internal/placeholder"
+)
+
+// DefaultFrontMatterKeys are the front matter keys whose values are translated
+// by default.
+var DefaultFrontMatterKeys = []string{"title", "description", "summary", "subtitle", "excerpt"}
+
+var (
+	fenceOpen      = regexp.MustCompile("^[ \t]*(`{3,}|~{3,})")
+	atxHeading     = regexp.MustCompile(`^( {0,3}#{1,6}[ \t]+)(.*?)((?:[ \t]+#+)?(?:[ \t]*\{#[^}]*\})?[ \t]*)$`)
+	headingID      = regexp.MustCompile(`\{#[^}]*\}`)
+	setextLine     = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	thematicBreak  = regexp.MustCompile(`^ {0,3}(?:(?:-[ \t]*){3,}|(?:\*[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	linkDefinition = regexp.MustCompile(`^ {0,3}\[[^\]]+\]:[ \t]*\S`)
+	htmlBlock      = regexp.MustCompile(`^ {0,3}<(?:[A-Za-z][A-Za-z0-9-]*(?:[\s/>]|$)|/[A-Za-z]|!|\?)`)
+	tableDelimiter = regexp.MustCompile(`^ {0,3}\|?[ \t]*:?-+:?[ \t]*(\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+	blockPrefix    = regexp.MustCompile(`^[ \t]*(?:>[ \t]?)*[ \t]*(?:(?:[-*+]|\d{1,9}[.)])[ \t]+(?:\[[ xX]\][ \t]+)?)?`)
+	yamlEntry      = regexp.MustCompile(`^([A-Za-z0-9_-]+)([ \t]*:[ \t]+)(.*?)([ \t]*)$`)
+	tomlEntry      = regexp.MustCompile(`^([A-Za-z0-9_-]+)([ \t]*=[ \t]*)(".*")([ \t]*)$`)
+)
+
+// Translator translates Markdown documents with a gt.Translator. It is itself
+// a gt.Translator, so it can be used with the middlewares of the gt package.
+type Translator struct {
+	translator      gt.Translator
+	frontMatterKeys map[string]bool
+	headingIDs      bool
+}
+
+// Option is a functional option for configuring New.
+type Option func(*Translator)
+
+// WithFrontMatterKeys sets the top-level front matter keys whose values are
+// translated, DefaultFrontMatterKeys by default. Keys are never translated.
+func WithFrontMatterKeys(keys ...string) Option {
+	return func(t *Translator) {
+		t.frontMatterKeys = make(map[string]bool, len(keys))
+		for _, key := range keys {
+			t.frontMatterKeys[key] = true
+		}
+	}
+}
+
+// WithHeadingIDs adds an explicit {#id} attribute, derived from the original
+// text the way GitHub derives anchors, to headings without one. Links to the
+// headings then keep working after translation on renderers supporting
+// heading attributes, such as Hugo, Pandoc or kramdown.
+func WithHeadingIDs() Option {
+	return func(t *Translator) {
+		t.headingIDs = true
+	}
+}
+
+// New creates a Markdown Translator using translator.
+func New(translator gt.Translator, opts ...Option) *Translator {
+	t := &Translator{translator: translator}
+	WithFrontMatterKeys(DefaultFrontMatterKeys...)(t)
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Translate translates the prose of a Markdown document: paragraphs, headings,
+// list items, blockquotes, table cells, link texts, image descriptions and the
+// configured front matter values. Code blocks, inline code, HTML, link and
+// image targets, autolinks, URLs, link reference definitions and heading
+// anchors are kept as is. All texts are sent as a single batch; consecutive
+// lines of a paragraph are translated together and joined on one line.
+func (t *Translator) Translate(ctx context.Context, document, from, to string) (*gt.Translated, error) {
+	if strings.TrimSpace(document) == "" {
+		return nil, errs.New(errs.ErrEmptyText, "", 0, "Document is empty", nil)
+	}
+	d := &doc{translator: t}
+	d.parse(document)
+
+	result := &gt.Translated{}
+	if len(d.segments) > 0 {
+		texts := make([]string, len(d.segments))
+		for i, s := range d.segments {
+			texts[i] = s.text
+		}
+		results, err := gt.TranslateBatchWith(ctx, t.translator, texts, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for i, r := range results {
+			if r.Err != nil {
+				return nil, r.Err
+			}
+			d.segments[i].translate(r.Translated.Text)
+		}
+		*result = *results[0].Translated
+		result.Alternatives = nil
+		result.Pronunciation = nil
+	}
+	result.Text = d.render()
+	return result, nil
+}
+
+// doc is a document being translated: a sequence of literal parts and
+// translatable segments.
+type doc struct {
+	translator *Translator
+	parts      []part
+	segments   []*segment
+}
+
+// part is either literal text or a segment, with an optional quote applied
+// to the translation.
+type part struct {
+	literal string
+	segment *segment
+	quote   func(string) string
+}
+
+type segment struct {
+	text       string             // With inline markup protected by tokens
+	tokens     []string           // Protected markup
+	pairs      []placeholder.Pair // Tokens opening and closing link texts and HTML elements
+	translated string
+}
+
+// translate sets the translation of s to text, or keeps the source when the
+// backend lost or reordered its markup, which can't be put back sensibly.
+func (s *segment) translate(text string) {
+	if !placeholder.Intact(text, len(s.tokens), s.pairs) {
+		text = s.text
+	}
+	s.translated = placeholder.Restore(text, s.tokens)
+}
+
+func (d *doc) literal(s string) {
+	d.parts = append(d.parts, part{literal: s})
+}
+
+// text adds a translatable text, keeping its surrounding whitespace.
+func (d *doc) text(s string) {
+	d.quoted(s, nil)
+}
+
+func (d *doc) quoted(s string, quote func(string) string) {
+	body := strings.TrimSpace(s)
+	if body == "" {
+		d.literal(s)
+		return
+	}
+	start := strings.Index(s, body)
+	d.literal(s[:start])
+	text, tokens, pairs := protectInline(body)
+	if placeholder.Empty(text) {
+		// Nothing to translate, such as a cell holding only code
+		if quote != nil {
+			body = quote(body)
+		}
+		d.literal(body)
+	} else {
+		seg := &segment{text: text, tokens: tokens, pairs: pairs}
+		d.segments = append(d.segments, seg)
+		d.parts = append(d.parts, part{segment: seg, quote: quote})
+	}
+	d.literal(s[start+len(body):])
+}
+
+func (d *doc) render() string {
+	var b strings.Builder
+	for _, p := range d.parts {
+		if p.segment == nil {
+			b.WriteString(p.literal)
+			continue
+		}
+		text := p.segment.translated
+		if p.quote != nil {
+			text = p.quote(text)
+		}
+		b.WriteString(text)
+	}
+	return b.String()
+}
+
+// parse splits document into literal parts and segments.
+func (d *doc) parse(document string) {
+	lines := splitLines(document)
+	i := d.parseFrontMatter(lines)
+
+	var (
+		fence      string // Closing fence of the current fenced code block
+		inCode     bool   // In an indented code block
+		inComment  bool   // In a multi-line HTML comment
+		prevBlank  = true
+		inList     bool
+		paragraph  []string // Lines of the current paragraph
+		paraEnd    string   // Hard line break and line ending of its last line
+		flushParas = func() {
+			if len(paragraph) > 0 {
+				d.text(strings.Join(paragraph, " "))
+				d.literal(paraEnd)
+				paragraph = nil
+			}
+		}
+	)
+
+	for ; i < len(lines); i++ {
+		line, eol := lines[i].text, lines[i].eol
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+			d.literal(line + eol)
+			continue
+		case inComment:
+			if strings.Contains(line, "-->") {
+				inComment = false
+			}
+			d.literal(line + eol)
+			continue
+		}
+
+		if trimmed == "" {
+			flushParas()
+			d.literal(line + eol)
+			prevBlank = true
+			continue
+		}
+
+		isIndented := strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t")
+		if isIndented && (inCode || prevBlank && !inList) {
+			inCode = true
+			d.literal(line + eol)
+			continue
+		}
+		inCode = false
+		wasBlank := prevBlank
+		prevBlank = false
+
+		if m := fenceOpen.FindStringSubmatch(line); m != nil {
+			flushParas()
+			fence = m[1]
+			d.literal(line + eol)
+			continue
+		}
+		if len(paragraph) > 0 && setextLine.MatchString(line) {
+			flushParas()
+			d.literal(line + eol)
+			continue
+		}
+		if thematicBreak.MatchString(line) || linkDefinition.MatchString(line) {
+			flushParas()
+			d.literal(line + eol)
+			continue
+		}
+		if htmlBlock.MatchString(line) {
+			flushParas()
+			if strings.Contains(line, "<!--") && !strings.Contains(line, "-->") {
+				inComment = true
+			}
+			d.literal(line + eol)
+			continue
+		}
+		if m := atxHeading.FindStringSubmatch(line); m != nil {
+			flushParas()
+			d.literal(m[1])
+			d.text(m[2])
+			suffix := m[3]
+			if d.translator.headingIDs && !headingID.MatchString(suffix) {
+				suffix = " {#" + slug(m[2]) + "}" + suffix
+			}
+			d.literal(suffix + eol)
+			inList = false
+			continue
+		}
+		if strings.Contains(line, "|") && (isTableRow(lines, i) || tableDelimiter.MatchString(line)) {
+			flushParas()
+			if tableDelimiter.MatchString(line) {
+				d.literal(line + eol)
+			} else {
+				d.tableRow(line)
+				d.literal(eol)
+			}
+			continue
+		}
+
+		prefix := blockPrefix.FindString(line)
+		if marker := strings.TrimSpace(prefix); marker != "" || isIndented {
+			// List item, blockquote or indented continuation
+			flushParas()
+			if strings.ContainsAny(marker, "-*+.)") {
+				inList = true
+			}
+			d.literal(prefix)
+			d.text(line[len(prefix):])
+			d.literal(eol)
+			continue
+		}
+		if wasBlank {
+			inList = false
+		}
+
+		if len(paragraph) == 0 {
+			d.literal(line[:len(line)-len(strings.TrimLeft(line, " \t"))])
+		}
+		paraEnd = eol
+		switch {
+		case strings.HasSuffix(line, "  "):
+			paraEnd = line[len(strings.TrimRight(line, " ")):] + eol
+		case strings.HasSuffix(trimmed, "\\"):
+			trimmed = strings.TrimSuffix(trimmed, "\\")
+			paraEnd = "\\" + eol
+		}
+		paragraph = append(paragraph, trimmed)
+		if paraEnd != eol {
+			// A hard line break ends the text translated together
+			flushParas()
+		}
+	}
+	flushParas()
+}
+
+// parseFrontMatter handles YAML (---) or TOML (+++) front matter at the start
+// of lines, translating the values of the configured keys. It returns the
+// index of the first line after it.
+func (d *doc) parseFrontMatter(lines []line) int {
+	if len(lines) == 0 || lines[0].text != "---" && lines[0].text != "+++" {
+		return 0
+	}
+	delimiter := lines[0].text
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if lines[i].text == delimiter || delimiter == "---" && lines[i].text == "..." {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return 0
+	}
+
+	d.literal(lines[0].text + lines[0].eol)
+	for _, l := range lines[1:end] {
+		entry := yamlEntry
+		if delimiter == "+++" {
+			entry = tomlEntry
+		}
+		m := entry.FindStringSubmatch(l.text)
+		if m == nil || !d.translator.frontMatterKeys[m[1]] {
+			d.literal(l.text + l.eol)
+			continue
+		}
+		value, quote, ok := unquoteValue(m[3], delimiter == "+++")
+		if !ok {
+			d.literal(l.text + l.eol)
+			continue
+		}
+		d.literal(m[1] + m[2])
+		d.quoted(value, quote)
+		d.literal(m[4] + l.eol)
+	}
+	d.literal(lines[end].text + lines[end].eol)
+	return end + 1
+}
+
+// tableRow adds a table row, translating every cell.
+func (d *doc) tableRow(row string) {
+	var cell strings.Builder
+	inCode := false
+	for i := 0; i < len(row); i++ {
+		c := row[i]
+		switch {
+		case c == '\\' && i+1 < len(row):
+			cell.WriteByte(c)
+			cell.WriteByte(row[i+1])
+			i++
+			continue
+		case c == '`':
+			inCode = !inCode
+		case c == '|' && !inCode:
+			d.text(cell.String())
+			cell.Reset()
+			d.literal("|")
+			continue
+		}
+		cell.WriteByte(c)
+	}
+	d.text(cell.String())
+}
+
+// isTableRow tells whether lines[i] belongs to a table: it is followed by a
+// delimiter row, or preceded by rows up to one.
+func isTableRow(lines []line, i int) bool {
+	if i+1 < len(lines) && tableDelimiter.MatchString(lines[i+1].text) && strings.Contains(lines[i+1].text, "-") {
+		return true
+	}
+	for j := i - 1; j >= 0 && strings.Contains(lines[j].text, "|"); j-- {
+		if tableDelimiter.MatchString(lines[j].text) {
+			return true
+		}
+	}
+	return false
+}
+
+// unquoteValue returns a front matter value without its quotes, and the
+// function quoting a translation the same way. Block scalars, flow
+// collections and anchors aren't supported.
+func unquoteValue(value string, toml bool) (string, func(string) string, bool) {
+	switch {
+	case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+		unquoted := strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(value[1 : len(value)-1])
+		return unquoted, doubleQuote, true
+	case toml:
+		return "", nil, false
+	case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'"), func(s string) string {
+			return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+		}, true
+	case value == "" || strings.ContainsAny(value[:1], "|>[{&*!%@`#"):
+		return "", nil, false
+	}
+	return value, func(s string) string {
+		if s == "" {
+			return `""`
+		}
+		if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.ContainsAny(s[:1], `"'|>[{&*!%@#-?:`) {
+			return doubleQuote(s)
+		}
+		return s
+	}, true
+}
+
+func doubleQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// slug derives a heading anchor the way GitHub does.
+func slug(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(heading)) {
+		switch {
+		case r == ' ':
+			b.WriteByte('-')
+		case r == '-' || r == '_' || isWordChar(r):
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+type line struct {
+	text, eol string
+}
+
+// splitLines splits s into lines and their line endings.
+func splitLines(s string) []line {
+	var lines []line
+	for s != "" {
+		i := strings.IndexByte(s, '\n')
+		if i < 0 {
+			lines = append(lines, line{text: s})
+			break
+		}
+		text, eol := s[:i], "\n"
+		if strings.HasSuffix(text, "\r") {
+			text, eol = text[:len(text)-1], "\r\n"
+		}
+		lines = append(lines, line{text: text, eol: eol})
+		s = s[i+1:]
+	}
+	return lines
+}
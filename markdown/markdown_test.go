@@ -0,0 +1,165 @@
+package markdown
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gt "gopkg.gilang.dev/translator/v2"
+	"gopkg.gilang.dev/translator/v2/internal/placeholder"
+	"gopkg.gilang.dev/translator/v2/internal/translatortest"
+)
+
+func translate(t *testing.T, document string, opts ...Option) (string, *translatortest.Upper) {
+	t.Helper()
+	u := &translatortest.Upper{}
+	result, err := New(u, opts...).Translate(context.Background(), document, "en", "id")
+	require.NoError(t, err)
+	assert.Equal(t, "upper", result.Backend)
+	return result.Text, u
+}
+
+func TestTranslate(t *testing.T) {
+	document := "---\n" +
+		"title: Getting started\n" +
+		"description: \"Install it: quickly\"\n" +
+		"layout: docs\n" +
+		"tags: [go, cli]\n" +
+		"---\n" +
+		"\n" +
+		"# Getting started {#start}\n" +
+		"\n" +
+		"Install the tool with `go install` and read\n" +
+		"the [guide](https://example.com/guide \"Guide\") or ![a logo](logo.png).\n" +
+		"\n" +
+		"```go\n" +
+		"fmt.Println(\"hello\")\n" +
+		"```\n" +
+		"\n" +
+		"    indented code\n" +
+		"\n" +
+		"- First item with <https://example.com>\n" +
+		"- [x] Done item\n" +
+		"  1. Nested *item*\n" +
+		"\n" +
+		"> Quoted text\\\n" +
+		"> on two lines\n" +
+		"\n" +
+		"| Name | Description |\n" +
+		"|------|:-----------:|\n" +
+		"| `id` | The identifier \\| key |\n" +
+		"\n" +
+		"<div align=\"center\">\n" +
+		"  <img src=\"x.png\">\n" +
+		"</div>\n" +
+		"\n" +
+		"See https://example.com/docs. Also [the API][api] and a footnote[^1].\n" +
+		"\n" +
+		"[api]: https://example.com/api\n" +
+		"\n" +
+		"***\n"
+
+	want := "---\n" +
+		"title: GETTING STARTED\n" +
+		"description: \"INSTALL IT: QUICKLY\"\n" +
+		"layout: docs\n" +
+		"tags: [go, cli]\n" +
+		"---\n" +
+		"\n" +
+		"# GETTING STARTED {#start}\n" +
+		"\n" +
+		"INSTALL THE TOOL WITH `go install` AND READ THE [GUIDE](https://example.com/guide \"Guide\") OR ![A LOGO](logo.png).\n" +
+		"\n" +
+		"```go\n" +
+		"fmt.Println(\"hello\")\n" +
+		"```\n" +
+		"\n" +
+		"    indented code\n" +
+		"\n" +
+		"- FIRST ITEM WITH <https://example.com>\n" +
+		"- [x] DONE ITEM\n" +
+		"  1. NESTED *ITEM*\n" +
+		"\n" +
+		"> QUOTED TEXT\\\n" +
+		"> ON TWO LINES\n" +
+		"\n" +
+		"| NAME | DESCRIPTION |\n" +
+		"|------|:-----------:|\n" +
+		"| `id` | THE IDENTIFIER \\| KEY |\n" +
+		"\n" +
+		"<div align=\"center\">\n" +
+		"  <img src=\"x.png\">\n" +
+		"</div>\n" +
+		"\n" +
+		"SEE https://example.com/docs. ALSO [THE API][api] AND A FOOTNOTE[^1].\n" +
+		"\n" +
+		"[api]: https://example.com/api\n" +
+		"\n" +
+		"***\n"
+
+	got, u := translate(t, document)
+	assert.Equal(t, want, got)
+	for _, text := range u.Texts() {
+		assert.NotContains(t, text, "https://")
+		assert.NotContains(t, text, "go install")
+	}
+}
+
+func TestTranslateParagraphs(t *testing.T) {
+	got, u := translate(t, "First line\nsecond line.  \nAfter break.\r\n\r\nNext paragraph\r\n")
+	assert.Equal(t, "FIRST LINE SECOND LINE.  \nAFTER BREAK.\r\n\r\nNEXT PARAGRAPH\r\n", got)
+	assert.ElementsMatch(t, []string{"First line second line.", "After break.", "Next paragraph"}, u.Texts())
+}
+
+func TestTranslateHeadingIDs(t *testing.T) {
+	got, _ := translate(t, "## Getting Started!\n### Keep {#kept}\n", WithHeadingIDs())
+	assert.Equal(t, "## GETTING STARTED! {#getting-started}\n### KEEP {#kept}\n", got)
+}
+
+func TestTranslateFrontMatter(t *testing.T) {
+	got, _ := translate(t, "+++\ntitle = \"Hello\"\nsummary = \"Not translated\"\n+++\nBody\n", WithFrontMatterKeys("title"))
+	assert.Equal(t, "+++\ntitle = \"HELLO\"\nsummary = \"Not translated\"\n+++\nBODY\n", got)
+
+	got, _ = translate(t, "---\ntitle: 'It''s here'\n---\n")
+	assert.Equal(t, "---\ntitle: 'IT''S HERE'\n---\n", got)
+}
+
+func TestProtectInline(t *testing.T) {
+	text, tokens, pairs := protectInline("Use `a` and [b](c) here\\*")
+	assert.Equal(t, "Use ⟦0⟧ and ⟦1⟧b⟦2⟧ here⟦3⟧", text)
+	assert.Equal(t, []string{"`a`", "[", "](c)", `\*`}, tokens)
+	assert.Equal(t, []placeholder.Pair{{Open: 1, Close: 2}}, pairs)
+
+	_, _, pairs = protectInline("A <b>[bold](x)</b> <br> link")
+	assert.Equal(t, []placeholder.Pair{{Open: 1, Close: 2}, {Open: 0, Close: 3}}, pairs)
+
+	// Spaces added around placeholders and dropped placeholders
+	assert.Equal(t, "Pakai `a` dan [b](c) ⟦9⟧ \\*", placeholder.Restore("Pakai ⟦ 0 ⟧ dan ⟦1⟧b⟦2 ⟧ ⟦9⟧", tokens))
+}
+
+func TestTranslateEmpty(t *testing.T) {
+	_, err := New(&translatortest.Upper{}).Translate(context.Background(), " \n", "en", "id")
+	assert.ErrorIs(t, err, gt.ErrEmptyText)
+
+	u := &translatortest.Upper{}
+	result, err := New(u).Translate(context.Background(), "```\ncode\n```\n", "en", "id")
+	require.NoError(t, err)
+	assert.Equal(t, "```\ncode\n```\n", result.Text)
+	assert.Empty(t, u.Texts())
+}
+
+func TestTranslateBrokenMarkup(t *testing.T) {
+	for name, rewrite := range map[string]func(string) string{
+		"dropped": func(text string) string { return strings.Replace(text, "⟦1⟧", "", 1) },
+		"swapped": strings.NewReplacer("⟦0⟧", "⟦1⟧", "⟦1⟧", "⟦0⟧").Replace,
+	} {
+		t.Run(name, func(t *testing.T) {
+			u := &translatortest.Upper{Rewrite: rewrite}
+			result, err := New(u).Translate(context.Background(), "Click [here](x) to go.\n\nSee <b>this</b> now.\n\nPlain text.\n", "en", "id")
+			require.NoError(t, err)
+			assert.Equal(t, "Click [here](x) to go.\n\nSee <b>this</b> now.\n\nPLAIN TEXT.\n", result.Text)
+		})
+	}
+}
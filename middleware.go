@@ -0,0 +1,228 @@
+package gt
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+	"gopkg.gilang.dev/translator/v2/ratelimit"
+)
+
+// Middleware wraps a Translator to add behavior around its calls.
+type Middleware func(Translator) Translator
+
+// Chain wraps translator with mws. The first middleware is the outermost one,
+// so Chain(t, a, b) calls a, then b, then t.
+func Chain(translator Translator, mws ...Middleware) Translator {
+	for i := len(mws) - 1; i >= 0; i-- {
+		translator = mws[i](translator)
+	}
+	return translator
+}
+
+// Retry is a Middleware version of NewRetryTranslator.
+func Retry(opts ...RetryOption) Middleware {
+	return func(next Translator) Translator {
+		return NewRetryTranslator(next, opts...)
+	}
+}
+
+// Cache is a Middleware version of NewCacheTranslator.
+func Cache(store Store, opts ...CacheOption) Middleware {
+	return func(next Translator) Translator {
+		return NewCacheTranslator(next, store, opts...)
+	}
+}
+
+// RateLimit is a Middleware version of NewRateLimitTranslator.
+func RateLimit(limiter *ratelimit.Limiter) Middleware {
+	return func(next Translator) Translator {
+		return NewRateLimitTranslator(next, limiter)
+	}
+}
+
+// Fallback is a Middleware version of NewFallbackTranslator, using the wrapped
+// translator as primary.
+func Fallback(secondary ...Translator) Middleware {
+	return func(next Translator) Translator {
+		return NewFallbackTranslator(next, secondary...)
+	}
+}
+
+// loggingTranslator logs every call of the translator.
+type loggingTranslator struct {
+	next   Translator
+	logger *slog.Logger
+}
+
+// Logging logs every translation with its languages, length, backend and
+// duration: at debug level on success and warning level on failure. Texts
+// themselves are never logged. A nil logger uses slog.Default.
+func Logging(logger *slog.Logger) Middleware {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(next Translator) Translator {
+		return &loggingTranslator{next: next, logger: logger}
+	}
+}
+
+func (l *loggingTranslator) Translate(ctx context.Context, text, from, to string) (*Translated, error) {
+	start := time.Now()
+	result, err := l.next.Translate(ctx, text, from, to)
+	attrs := []slog.Attr{
+		slog.String("from", from),
+		slog.String("to", to),
+		slog.Int("chars", utf8.RuneCountInString(text)),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if err != nil {
+		l.logger.LogAttrs(ctx, slog.LevelWarn, "translate failed", append(attrs, slog.Any("error", err))...)
+		return nil, err
+	}
+	l.logger.LogAttrs(ctx, slog.LevelDebug, "translate", append(attrs, slog.String("backend", result.Backend))...)
+	return result, nil
+}
+
+func (l *loggingTranslator) TranslateBatch(ctx context.Context, texts []string, from, to string) ([]BatchResult, error) {
+	start := time.Now()
+	results, err := batchWith(ctx, l.next, texts, from, to)
+	attrs := []slog.Attr{
+		slog.String("from", from),
+		slog.String("to", to),
+		slog.Int("texts", len(texts)),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if err != nil {
+		l.logger.LogAttrs(ctx, slog.LevelWarn, "translate batch failed", append(attrs, slog.Any("error", err))...)
+		return nil, err
+	}
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	level := slog.LevelDebug
+	if failed > 0 {
+		level = slog.LevelWarn
+	}
+	l.logger.LogAttrs(ctx, level, "translate batch", append(attrs, slog.Int("failed", failed))...)
+	return results, nil
+}
+
+// timingTranslator reports the duration of every call of the translator.
+type timingTranslator struct {
+	next    Translator
+	observe func(time.Duration, error)
+}
+
+// Timing calls observe with the duration and error of every call, for example
+// to feed a metrics histogram. A batch counts as a single call.
+func Timing(observe func(d time.Duration, err error)) Middleware {
+	return func(next Translator) Translator {
+		return &timingTranslator{next: next, observe: observe}
+	}
+}
+
+func (t *timingTranslator) Translate(ctx context.Context, text, from, to string) (*Translated, error) {
+	start := time.Now()
+	result, err := t.next.Translate(ctx, text, from, to)
+	t.observe(time.Since(start), err)
+	return result, err
+}
+
+func (t *timingTranslator) TranslateBatch(ctx context.Context, texts []string, from, to string) ([]BatchResult, error) {
+	start := time.Now()
+	results, err := batchWith(ctx, t.next, texts, from, to)
+	t.observe(time.Since(start), err)
+	return results, err
+}
+
+// inputTranslator rewrites texts before they reach the translator.
+type inputTranslator struct {
+	next      Translator
+	transform func(string) string
+}
+
+// TransformInput rewrites every text with transform before translating it.
+func TransformInput(transform func(string) string) Middleware {
+	return func(next Translator) Translator {
+		return &inputTranslator{next: next, transform: transform}
+	}
+}
+
+// NormalizeInput trims surrounding whitespace, collapses runs of spaces and
+// tabs, and converts texts to Unicode NFC before translating them.
+func NormalizeInput() Middleware {
+	return TransformInput(normalizeText)
+}
+
+// normalizeText implements NormalizeInput. Line breaks are kept.
+func normalizeText(text string) string {
+	lines := strings.Split(norm.NFC.String(text), "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.FieldsFunc(line, func(r rune) bool {
+			return r == ' ' || r == '\t' || r == '\r'
+		}), " ")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func (t *inputTranslator) Translate(ctx context.Context, text, from, to string) (*Translated, error) {
+	return t.next.Translate(ctx, t.transform(text), from, to)
+}
+
+func (t *inputTranslator) TranslateBatch(ctx context.Context, texts []string, from, to string) ([]BatchResult, error) {
+	transformed := make([]string, len(texts))
+	for i, text := range texts {
+		transformed[i] = t.transform(text)
+	}
+	return batchWith(ctx, t.next, transformed, from, to)
+}
+
+// outputTranslator post-processes results of the translator.
+type outputTranslator struct {
+	next    Translator
+	process func(*Translated)
+}
+
+// PostProcess calls process on every successful result before returning it.
+// process may modify the result in place.
+func PostProcess(process func(*Translated)) Middleware {
+	return func(next Translator) Translator {
+		return &outputTranslator{next: next, process: process}
+	}
+}
+
+// TransformOutput rewrites the translated text of every successful result.
+func TransformOutput(transform func(string) string) Middleware {
+	return PostProcess(func(result *Translated) {
+		result.Text = transform(result.Text)
+	})
+}
+
+func (t *outputTranslator) Translate(ctx context.Context, text, from, to string) (*Translated, error) {
+	result, err := t.next.Translate(ctx, text, from, to)
+	if err != nil {
+		return nil, err
+	}
+	t.process(result)
+	return result, nil
+}
+
+func (t *outputTranslator) TranslateBatch(ctx context.Context, texts []string, from, to string) ([]BatchResult, error) {
+	results, err := batchWith(ctx, t.next, texts, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		if result.Err == nil && result.Translated != nil {
+			t.process(result.Translated)
+		}
+	}
+	return results, nil
+}
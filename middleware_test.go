@@ -0,0 +1,100 @@
+package gt
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.gilang.dev/translator/v2/errs"
+)
+
+func TestChainOrder(t *testing.T) {
+	var order []string
+	mark := func(name string) Middleware {
+		return TransformInput(func(text string) string {
+			order = append(order, name)
+			return text + name
+		})
+	}
+
+	stub := &stubTranslator{fn: func(text, from, to string) (*Translated, error) {
+		return &Translated{Text: text}, nil
+	}}
+	result, err := Chain(stub, mark("a"), mark("b")).Translate(context.Background(), "x", "en", "id")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, order)
+	assert.Equal(t, "xab", result.Text)
+}
+
+func TestNormalizeInput(t *testing.T) {
+	assert.Equal(t, "Hello world\nsecond line", normalizeText("  Hello \t world  \r\n second   line \n"))
+	assert.Equal(t, "café", normalizeText("café"))
+}
+
+func TestTransformOutput(t *testing.T) {
+	translator := Chain(&stubTranslator{}, TransformOutput(strings.ToLower))
+	result, err := translator.Translate(context.Background(), "Hello", "en", "id")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", result.Text)
+
+	results, err := TranslateBatchWith(context.Background(), translator, []string{"One", "Two"}, "en", "id")
+	assert.NoError(t, err)
+	assert.Equal(t, "one", results[0].Translated.Text)
+	assert.Equal(t, "two", results[1].Translated.Text)
+}
+
+func TestTiming(t *testing.T) {
+	var observed []error
+	stub := &stubTranslator{fn: func(text, from, to string) (*Translated, error) {
+		return nil, errs.New(ErrNetwork, "stub", 0, "", nil)
+	}}
+	translator := Chain(stub, Timing(func(d time.Duration, err error) {
+		observed = append(observed, err)
+	}))
+
+	_, err := translator.Translate(context.Background(), "Hello", "en", "id")
+	assert.Error(t, err)
+	assert.Len(t, observed, 1)
+	assert.ErrorIs(t, observed[0], ErrNetwork)
+}
+
+func TestLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	translator := Chain(&stubTranslator{}, Logging(logger))
+	_, err := translator.Translate(context.Background(), "secret text", "en", "id")
+	assert.NoError(t, err)
+
+	assert.Contains(t, buf.String(), "msg=translate")
+	assert.Contains(t, buf.String(), "chars=11")
+	assert.NotContains(t, buf.String(), "secret")
+}
+
+func TestMiddlewareKeepsNativeBatch(t *testing.T) {
+	batcher := &batchStub{}
+	translator := Chain(batcher, Logging(slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))), NormalizeInput())
+
+	_, err := TranslateBatchWith(context.Background(), translator, []string{"a", "b", "c"}, "en", "id")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, batcher.batches)
+}
+
+// batchStub is a BatchTranslator counting its batch calls.
+type batchStub struct {
+	stubTranslator
+	batches int
+}
+
+func (b *batchStub) TranslateBatch(ctx context.Context, texts []string, from, to string) ([]BatchResult, error) {
+	b.batches++
+	results := make([]BatchResult, len(texts))
+	for i, text := range texts {
+		results[i].Translated = &Translated{Text: text}
+	}
+	return results, nil
+}
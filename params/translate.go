@@ -1,7 +1,37 @@
 package params
 
+// Formality values for Translate.Formality.
+const (
+	FormalityDefault = ""
+	FormalityMore    = "more"
+	FormalityLess    = "less"
+)
+
+// Tag handling values for Translate.TagHandling.
+const (
+	TagHandlingNone = ""
+	TagHandlingHTML = "html"
+	TagHandlingXML  = "xml"
+)
+
+// Translate describes a translation request. Only Text and To are required;
+// backends ignore the options they don't support.
 type Translate struct {
 	Text string `json:"text"`
 	From string `json:"from"`
 	To   string `json:"to"`
+
+	// Formality of the translation: FormalityMore or FormalityLess.
+	Formality string `json:"formality,omitempty"`
+	// Context is additional text that helps interpret Text. It isn't translated.
+	Context string `json:"context,omitempty"`
+	// Alternatives is the number of alternative translations to request.
+	// Zero uses the backend default, a negative value requests none.
+	Alternatives int `json:"alternatives,omitempty"`
+	// Glossary maps source terms to the translation that must be used for them.
+	Glossary map[string]string `json:"glossary,omitempty"`
+	// PreserveFormatting keeps line breaks and surrounding whitespace of Text.
+	PreserveFormatting bool `json:"preserve_formatting,omitempty"`
+	// TagHandling tells the backend that Text contains markup: TagHandlingHTML or TagHandlingXML.
+	TagHandling string `json:"tag_handling,omitempty"`
 }
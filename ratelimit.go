@@ -0,0 +1,37 @@
+package gt
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"gopkg.gilang.dev/translator/v2/ratelimit"
+)
+
+// rateLimitTranslator waits for a limiter before every call to the translator.
+type rateLimitTranslator struct {
+	translator Translator
+	limiter    *ratelimit.Limiter
+}
+
+// NewRateLimitTranslator creates a Translator that calls translator only when
+// limiter allows it. Share one limiter between translators to apply a common cap.
+func NewRateLimitTranslator(translator Translator, limiter *ratelimit.Limiter) Translator {
+	return &rateLimitTranslator{translator: translator, limiter: limiter}
+}
+
+func (r *rateLimitTranslator) Translate(ctx context.Context, text, from, to string) (*Translated, error) {
+	if err := r.limiter.Wait(ctx, utf8.RuneCountInString(text)); err != nil {
+		return nil, err
+	}
+	return r.translator.Translate(ctx, text, from, to)
+}
+
+// TranslateBatch implements BatchTranslator. Translators sending a batch in a
+// few requests, such as DeepL, wait for the limiter before each of them;
+// otherwise every text waits for the limiter on its own.
+func (r *rateLimitTranslator) TranslateBatch(ctx context.Context, texts []string, from, to string) ([]BatchResult, error) {
+	if batcher, ok := r.translator.(requestBatcher); ok {
+		return batcher.translateBatchRequests(ctx, texts, from, to, r.limiter)
+	}
+	return translateParallel(ctx, r, texts, from, to, DefaultBatchConcurrency), nil
+}
@@ -0,0 +1,137 @@
+// Package ratelimit provides a client-side rate limiter shared by translation
+// clients, capping both requests per second and characters per minute.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gopkg.gilang.dev/translator/v2/errs"
+)
+
+// Limiter is a concurrency-safe token bucket limiter. Callers are served in
+// the order they call Wait, so a steady stream of small requests can't starve
+// a large one.
+type Limiter struct {
+	mu       sync.Mutex
+	requests *bucket
+	chars    *bucket
+	now      func() time.Time
+}
+
+// Option is a functional option for configuring Limiter.
+type Option func(*Limiter)
+
+// WithRequestsPerSecond caps requests to rps per second, allowing bursts of up
+// to burst requests.
+func WithRequestsPerSecond(rps float64, burst int) Option {
+	return func(l *Limiter) {
+		l.requests = newBucket(rps, burst)
+	}
+}
+
+// WithCharsPerMinute caps translated characters to n per minute. Up to a full
+// minute worth of characters may be sent at once.
+func WithCharsPerMinute(n int) Option {
+	return func(l *Limiter) {
+		l.chars = newBucket(float64(n)/60, n)
+	}
+}
+
+// New creates a Limiter with the given options. A Limiter without options
+// never waits.
+func New(opts ...Option) *Limiter {
+	l := &Limiter{now: time.Now}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Wait blocks until a request translating chars characters is allowed. It
+// returns immediately with an error wrapping errs.ErrRateLimited and
+// context.DeadlineExceeded if ctx would expire before then, or with ctx's
+// error if ctx is done while waiting.
+func (l *Limiter) Wait(ctx context.Context, chars int) error {
+	if l == nil {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	now := l.now()
+	delay := max(l.requests.reserve(now, 1), l.chars.reserve(now, float64(chars)))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && deadline.Sub(now) < delay {
+		l.cancel(chars)
+		return errs.New(errs.ErrRateLimited, "", 0, "rate limit wait exceeds context deadline", context.DeadlineExceeded)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.cancel(chars)
+		return ctx.Err()
+	}
+}
+
+// cancel gives back the tokens of a reservation that won't be used.
+func (l *Limiter) cancel(chars int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.requests.restore(1)
+	l.chars.restore(float64(chars))
+}
+
+// bucket is a token bucket refilled continuously at rate tokens per second.
+// Tokens may go negative, which queues later reservations behind earlier ones.
+type bucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newBucket(rate float64, burst int) *bucket {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &bucket{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
+
+// reserve takes n tokens and returns how long the caller must wait for them.
+func (b *bucket) reserve(now time.Time, n float64) time.Duration {
+	if b == nil {
+		return 0
+	}
+	if !b.last.IsZero() {
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+	b.tokens -= n
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// restore gives back n tokens.
+func (b *bucket) restore(n float64) {
+	if b == nil {
+		return
+	}
+	b.tokens = min(b.burst, b.tokens+n)
+}
@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gopkg.gilang.dev/translator/v2/errs"
+)
+
+func TestLimiterUnlimited(t *testing.T) {
+	l := New()
+	for i := 0; i < 100; i++ {
+		if err := l.Wait(context.Background(), 1000); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var nilLimiter *Limiter
+	if err := nilLimiter.Wait(context.Background(), 10); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLimiterRequestsPerSecond(t *testing.T) {
+	l := New(WithRequestsPerSecond(20, 2))
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := l.Wait(context.Background(), 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Two requests fit in the burst, the other two wait 50ms each
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Expected limiter to wait, took %v", elapsed)
+	}
+}
+
+func TestLimiterCharsPerMinute(t *testing.T) {
+	now := time.Now()
+	l := New(WithCharsPerMinute(60))
+	l.now = func() time.Time { return now }
+
+	if d := l.chars.reserve(now, 60); d != 0 {
+		t.Errorf("Expected burst to be allowed, got %v", d)
+	}
+	if d := l.chars.reserve(now, 30); d != 30*time.Second {
+		t.Errorf("Expected 30s wait, got %v", d)
+	}
+}
+
+func TestLimiterDeadline(t *testing.T) {
+	l := New(WithRequestsPerSecond(1, 1))
+	if err := l.Wait(context.Background(), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := l.Wait(ctx, 0)
+	if !errors.Is(err, errs.ErrRateLimited) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected rate limited deadline error, got %v", err)
+	}
+
+	// The canceled reservation must not delay the next caller further
+	if d := l.requests.reserve(time.Now(), 0); d > time.Second {
+		t.Errorf("Expected canceled reservation to be restored, got %v", d)
+	}
+}
@@ -0,0 +1,100 @@
+package gt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.gilang.dev/translator/v2/deepl"
+	"gopkg.gilang.dev/translator/v2/ratelimit"
+)
+
+func TestRateLimitTranslator(t *testing.T) {
+	stub := &stubTranslator{}
+	translator := NewRateLimitTranslator(stub, ratelimit.New(ratelimit.WithRequestsPerSecond(1, 1)))
+
+	_, err := translator.Translate(context.Background(), "hello", "en", "id")
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = translator.Translate(ctx, "hello", "en", "id")
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Equal(t, int32(1), stub.calls)
+}
+
+func TestRateLimitTranslatorBatch(t *testing.T) {
+	// Texts of a batch translated one by one each take a request token.
+	batcher := &batchStub{}
+	limiter := ratelimit.New(ratelimit.WithRequestsPerSecond(1, 2))
+	translator := NewRateLimitTranslator(batcher, limiter)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	results, err := TranslateBatchWith(ctx, translator, []string{"a", "b", "c"}, "en", "id")
+	assert.NoError(t, err)
+	assert.Zero(t, batcher.batches)
+	var limited int
+	for _, result := range results {
+		if result.Err != nil {
+			assert.ErrorIs(t, result.Err, ErrRateLimited)
+			limited++
+		}
+	}
+	assert.Equal(t, 1, limited)
+	assert.Equal(t, int32(2), batcher.calls)
+}
+
+func TestRateLimitTranslatorRequestBatch(t *testing.T) {
+	// Texts sent together take a request token per request.
+	batcher := &requestBatchStub{}
+	translator := NewRateLimitTranslator(batcher, ratelimit.New(ratelimit.WithRequestsPerSecond(1, 1)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	results, err := TranslateBatchWith(ctx, translator, []string{"a", "b", "c"}, "en", "id")
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+	assert.Equal(t, 1, batcher.batches)
+	assert.Zero(t, batcher.calls)
+	assert.Equal(t, "a", results[0].Translated.Text)
+	assert.Equal(t, "b", results[1].Translated.Text)
+	assert.ErrorIs(t, results[2].Err, ErrRateLimited)
+}
+
+func TestRateLimitTranslatorDeepLBatch(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.WithRequestsPerSecond(1, 1))
+	assert.NoError(t, limiter.Wait(context.Background(), 0))
+	adapter := &deeplAdapter{client: deepl.New(deepl.WithMaxChars(5))}
+
+	// Both requests wait for the limiter, and give up before sending anything
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	results, err := NewRateLimitTranslator(adapter, limiter).(BatchTranslator).TranslateBatch(ctx, []string{"hello", "world"}, "en", "de")
+	assert.NoError(t, err)
+	for _, result := range results {
+		assert.ErrorIs(t, result.Err, ErrRateLimited)
+	}
+}
+
+// requestBatchStub is a batchStub sending two texts per request.
+type requestBatchStub struct {
+	batchStub
+}
+
+func (b *requestBatchStub) translateBatchRequests(ctx context.Context, texts []string, from, to string, limiter *ratelimit.Limiter) ([]BatchResult, error) {
+	b.batches++
+	results := make([]BatchResult, len(texts))
+	for start := 0; start < len(texts); start += 2 {
+		err := limiter.Wait(ctx, 0)
+		for i := start; i < min(start+2, len(texts)); i++ {
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+			results[i].Translated = &Translated{Text: texts[i]}
+		}
+	}
+	return results, nil
+}
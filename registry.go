@@ -0,0 +1,114 @@
+package gt
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"gopkg.gilang.dev/translator/v2/deepl"
+	"gopkg.gilang.dev/translator/v2/googletranslate"
+	"gopkg.gilang.dev/translator/v2/ratelimit"
+)
+
+// Config holds the settings passed to a Factory. Backends ignore the
+// settings they don't support.
+type Config struct {
+	Host        string             // API host, the backend default if empty
+	ProxyURL    string             // Outbound proxy
+	Timeout     time.Duration      // HTTP client timeout, none if zero
+	RateLimiter *ratelimit.Limiter // Limiter shared by all requests of the client
+	Options     map[string]string  // Backend-specific settings, such as "dl_session" for DeepL
+}
+
+// Factory creates a Translator from a Config.
+type Factory func(config Config) (Translator, error)
+
+var (
+	registryMu sync.RWMutex
+	factories  = map[TranslatorType]Factory{}
+)
+
+func init() {
+	Register(Google, newGoogleFromConfig)
+	Register(DeepL, newDeepLFromConfig)
+}
+
+// Register makes a translator backend available by name to New. It panics if
+// factory is nil or a backend is already registered under name, so it is
+// meant to be called from init functions.
+func Register(name TranslatorType, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if factory == nil {
+		panic("gt: Register factory is nil")
+	}
+	if _, dup := factories[name]; dup {
+		panic("gt: Register called twice for translator " + string(name))
+	}
+	factories[name] = factory
+}
+
+// Registered returns the sorted names of the registered backends.
+func Registered() []TranslatorType {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]TranslatorType, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+// New creates a translator of the backend registered under name.
+func New(name TranslatorType, config Config) (Translator, error) {
+	registryMu.RLock()
+	factory, ok := factories[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown translator %q", name)
+	}
+	return factory(config)
+}
+
+// Use creates a translator with New and makes it the default translator.
+func Use(name TranslatorType, config Config) error {
+	translator, err := New(name, config)
+	if err != nil {
+		return err
+	}
+	SetDefaultTranslator(translator)
+	return nil
+}
+
+// httpClient returns the HTTP client described by config.
+func (c Config) httpClient() *http.Client {
+	return &http.Client{Timeout: c.Timeout}
+}
+
+func newGoogleFromConfig(config Config) (Translator, error) {
+	opts := []googletranslate.Option{
+		googletranslate.WithHTTPClient(config.httpClient()),
+		googletranslate.WithProxyURL(config.ProxyURL),
+		googletranslate.WithRateLimiter(config.RateLimiter),
+	}
+	if config.Host != "" {
+		opts = append(opts, googletranslate.WithHost(config.Host))
+	}
+	return NewGoogleTranslator(opts...), nil
+}
+
+func newDeepLFromConfig(config Config) (Translator, error) {
+	opts := []deepl.Option{
+		deepl.WithHTTPClient(config.httpClient()),
+		deepl.WithProxyURL(config.ProxyURL),
+		deepl.WithRateLimiter(config.RateLimiter),
+		deepl.WithDLSession(config.Options["dl_session"]),
+	}
+	if config.Host != "" {
+		opts = append(opts, deepl.WithHost(config.Host))
+	}
+	return NewDeepLTranslator(opts...), nil
+}
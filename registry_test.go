@@ -0,0 +1,62 @@
+package gt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.gilang.dev/translator/v2/deepl"
+)
+
+func TestRegistryBuiltins(t *testing.T) {
+	names := Registered()
+	assert.Contains(t, names, Google)
+	assert.Contains(t, names, DeepL)
+
+	translator, err := New(Google, Config{Host: "google.co.id", ProxyURL: "http://proxy:8080", Timeout: time.Second})
+	assert.NoError(t, err)
+	google := translator.(*googleTranslateAdapter).client
+	assert.Equal(t, "google.co.id", google.Host())
+	assert.Equal(t, "http://proxy:8080", google.ProxyURL())
+
+	translator, err = New(DeepL, Config{Options: map[string]string{"dl_session": "session"}})
+	assert.NoError(t, err)
+	client := translator.(*deeplAdapter).client
+	assert.Equal(t, deepl.DefaultHost, client.Host())
+	assert.Equal(t, "session", client.DLSession())
+}
+
+func TestRegistryCustom(t *testing.T) {
+	const name TranslatorType = "registry-test"
+	var got Config
+	Register(name, func(config Config) (Translator, error) {
+		got = config
+		return funcTranslator(func(ctx context.Context, text, from, to string) (*Translated, error) {
+			return &Translated{Text: text, Backend: string(name)}, nil
+		}), nil
+	})
+	defer func() {
+		registryMu.Lock()
+		delete(factories, name)
+		registryMu.Unlock()
+	}()
+
+	assert.Contains(t, Registered(), name)
+	assert.Panics(t, func() { Register(name, func(Config) (Translator, error) { return nil, nil }) })
+
+	original := getTranslator()
+	defer SetDefaultTranslator(original)
+
+	assert.NoError(t, Use(name, Config{Host: "example.com"}))
+	assert.Equal(t, "example.com", got.Host)
+	result, err := Translate(context.Background(), "hello", "id")
+	assert.NoError(t, err)
+	assert.Equal(t, string(name), result.Backend)
+}
+
+func TestRegistryUnknown(t *testing.T) {
+	_, err := New("unknown", Config{})
+	assert.Error(t, err)
+	assert.Panics(t, func() { Register("nil-factory", nil) })
+}
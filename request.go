@@ -0,0 +1,193 @@
+package gt
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+	"gopkg.gilang.dev/translator/v2/deepl"
+	"gopkg.gilang.dev/translator/v2/internal/placeholder"
+	"gopkg.gilang.dev/translator/v2/params"
+)
+
+// TranslateRequest describes a translation with per-call options.
+// It is the same type as params.Translate.
+type TranslateRequest = params.Translate
+
+type requestKey struct{}
+
+// ContextWithRequest returns a copy of ctx carrying the per-call options of
+// req, as DoWith does. Use it to pass options such as Alternatives to
+// TranslateBatchWith or TranslateHTMLWith.
+func ContextWithRequest(ctx context.Context, req TranslateRequest) context.Context {
+	return context.WithValue(ctx, requestKey{}, req)
+}
+
+// RequestFromContext returns the request being translated by Do, if any.
+// Translators use it to read per-call options such as Formality; they travel
+// in the context so that middlewares pass them through unchanged.
+func RequestFromContext(ctx context.Context) (TranslateRequest, bool) {
+	req, ok := ctx.Value(requestKey{}).(TranslateRequest)
+	return req, ok
+}
+
+// requestOptionsKey returns the options of the request in ctx that affect a
+// backend's translation, as a string suitable for cache keys.
+func requestOptionsKey(ctx context.Context) string {
+	req, ok := RequestFromContext(ctx)
+	if !ok || req.Formality == "" && req.Context == "" && req.Alternatives == 0 && req.TagHandling == "" {
+		return ""
+	}
+	return strings.Join([]string{req.Formality, strconv.Itoa(req.Alternatives), req.TagHandling, req.Context}, "\x00")
+}
+
+// deeplOptions converts the request in ctx into DeepL options.
+func deeplOptions(ctx context.Context) deepl.TranslateOptions {
+	req, _ := RequestFromContext(ctx)
+	return deepl.TranslateOptions{
+		Formality:    req.Formality,
+		Alternatives: req.Alternatives,
+		TagHandling:  req.TagHandling,
+		Context:      req.Context,
+	}
+}
+
+// Do translates req with the default translator.
+func Do(ctx context.Context, req TranslateRequest) (*Translated, error) {
+	return DoWith(ctx, getTranslator(), req)
+}
+
+// DoWith translates req using a specific translator. An empty req.From
+// detects the source language. Glossary and PreserveFormatting are applied
+// here for every backend; the other options are passed to the translator,
+// which reads them with RequestFromContext.
+func DoWith(ctx context.Context, translator Translator, req TranslateRequest) (*Translated, error) {
+	if req.Text == "" {
+		return nil, errTextRequired()
+	}
+	if req.To == "" {
+		return nil, errLanguageRequired("To")
+	}
+	if _, err := language.Parse(req.To); err != nil {
+		return nil, errLanguageInvalid("To", err)
+	}
+	if req.From == "" {
+		req.From = "auto"
+	} else if req.From != "auto" {
+		if _, err := language.Parse(req.From); err != nil {
+			return nil, errLanguageInvalid("From", err)
+		}
+	}
+
+	ctx = ContextWithRequest(ctx, req)
+
+	text, terms := protectGlossary(req.Text, req.Glossary)
+
+	var (
+		result *Translated
+		err    error
+	)
+	if req.PreserveFormatting {
+		result, err = translatePreservingFormat(ctx, translator, text, req.From, req.To)
+	} else {
+		result, err = translator.Translate(ctx, text, req.From, req.To)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(terms) > 0 {
+		result.Text = placeholder.Restore(result.Text, terms)
+		for i, alt := range result.Alternatives {
+			result.Alternatives[i] = placeholder.Restore(alt, terms)
+		}
+	}
+	return result, nil
+}
+
+// translatePreservingFormat translates every line of text on its own and puts
+// back the line breaks and the whitespace surrounding each line.
+func translatePreservingFormat(ctx context.Context, translator Translator, text, from, to string) (*Translated, error) {
+	spans, bodies, indexes := splitSpaces(strings.Split(text, "\n"))
+	if len(bodies) == 0 {
+		return nil, errTextRequired()
+	}
+
+	results, err := batchWith(ctx, translator, bodies, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for n, i := range indexes {
+		if results[n].Err != nil {
+			return nil, results[n].Err
+		}
+		spans[i].body = results[n].Translated.Text
+	}
+	return joinedResult(results[0].Translated, joinSpans(spans, "\n"), len(bodies)), nil
+}
+
+// protectGlossary replaces every whole-word occurrence of a glossary term in
+// text with a placeholder token, and returns the target of each token, to be
+// put back with placeholder.Restore.
+// Longer terms win over shorter ones starting at the same position.
+func protectGlossary(text string, glossary map[string]string) (string, []string) {
+	if len(glossary) == 0 {
+		return text, nil
+	}
+	sources := make([]string, 0, len(glossary))
+	for source := range glossary {
+		if source != "" {
+			sources = append(sources, source)
+		}
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		if len(sources[i]) != len(sources[j]) {
+			return len(sources[i]) > len(sources[j])
+		}
+		return sources[i] < sources[j]
+	})
+
+	var (
+		b       strings.Builder
+		targets []string
+	)
+	for i := 0; i < len(text); {
+		matched := false
+		if i == 0 || !isWordRune(lastRune(text[:i])) {
+			for _, source := range sources {
+				end := i + len(source)
+				if strings.HasPrefix(text[i:], source) && (end == len(text) || !isWordRune(firstRune(text[end:]))) {
+					b.WriteString(placeholder.Token(len(targets)))
+					targets = append(targets, glossary[source])
+					i = end
+					matched = true
+					break
+				}
+			}
+		}
+		if !matched {
+			_, size := utf8.DecodeRuneInString(text[i:])
+			b.WriteString(text[i : i+size])
+			i += size
+		}
+	}
+	return b.String(), targets
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+func firstRune(s string) rune {
+	r, _ := utf8.DecodeRuneInString(s)
+	return r
+}
+
+func lastRune(s string) rune {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return r
+}
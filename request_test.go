@@ -0,0 +1,108 @@
+package gt
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.gilang.dev/translator/v2/params"
+)
+
+func TestDoWithPassesOptions(t *testing.T) {
+	var seen TranslateRequest
+	translator := funcTranslator(func(ctx context.Context, text, from, to string) (*Translated, error) {
+		seen, _ = RequestFromContext(ctx)
+		return &Translated{Text: text}, nil
+	})
+
+	_, err := DoWith(context.Background(), Chain(translator, NormalizeInput()), TranslateRequest{
+		Text:      "Hello",
+		To:        params.GERMAN,
+		Formality: params.FormalityMore,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, params.FormalityMore, seen.Formality)
+	assert.Equal(t, "auto", seen.From)
+}
+
+func TestDoWithValidation(t *testing.T) {
+	stub := &stubTranslator{}
+	_, err := DoWith(context.Background(), stub, TranslateRequest{To: params.GERMAN})
+	assert.ErrorIs(t, err, ErrEmptyText)
+	_, err = DoWith(context.Background(), stub, TranslateRequest{Text: "Hello", To: "not a language"})
+	assert.ErrorIs(t, err, ErrInvalidLanguage)
+	assert.Zero(t, stub.calls)
+}
+
+func TestDoWithGlossary(t *testing.T) {
+	var sent string
+	translator := funcTranslator(func(ctx context.Context, text, from, to string) (*Translated, error) {
+		sent = text
+		// Simulate a backend adding spaces inside a placeholder
+		return &Translated{Text: strings.Replace(strings.ToUpper(text), "⟦1⟧", "⟦ 1 ⟧", 1)}, nil
+	})
+
+	result, err := DoWith(context.Background(), translator, TranslateRequest{
+		Text:     "Open the Dashboard in Gilang Cloud, not Cloudy.",
+		To:       params.INDONESIAN,
+		Glossary: map[string]string{"Cloud": "Awan", "Gilang Cloud": "Gilang Cloud", "Dashboard": "Dasbor"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Open the ⟦0⟧ in ⟦1⟧, not Cloudy.", sent)
+	assert.Equal(t, "OPEN THE Dasbor IN Gilang Cloud, NOT CLOUDY.", result.Text)
+
+	// Terms whose placeholder the backend dropped are kept
+	translator = funcTranslator(func(ctx context.Context, text, from, to string) (*Translated, error) {
+		return &Translated{Text: "Buka"}, nil
+	})
+	result, err = DoWith(context.Background(), translator, TranslateRequest{
+		Text:     "Open Dashboard",
+		To:       params.INDONESIAN,
+		Glossary: map[string]string{"Dashboard": "Dasbor"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Buka Dasbor", result.Text)
+}
+
+func TestDeepLOptions(t *testing.T) {
+	ctx := ContextWithRequest(context.Background(), TranslateRequest{
+		Formality:   params.FormalityLess,
+		Context:     "A button label",
+		TagHandling: "html",
+	})
+	opts := deeplOptions(ctx)
+	assert.Equal(t, params.FormalityLess, opts.Formality)
+	assert.Equal(t, "A button label", opts.Context)
+	assert.Equal(t, "html", opts.TagHandling)
+}
+
+func TestDoWithPreserveFormatting(t *testing.T) {
+	stub := &stubTranslator{}
+	result, err := DoWith(context.Background(), stub, TranslateRequest{
+		Text:               "  first line\n\n\tsecond line  \n",
+		To:                 params.INDONESIAN,
+		PreserveFormatting: true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "  FIRST LINE\n\n\tSECOND LINE  \n", result.Text)
+	assert.Equal(t, int32(2), stub.calls)
+}
+
+func TestCacheKeyIncludesOptions(t *testing.T) {
+	stub := &stubTranslator{}
+	translator := NewCacheTranslator(stub, &mapStore{entries: map[string]*Translated{}})
+
+	for _, formality := range []string{params.FormalityMore, params.FormalityLess, params.FormalityMore} {
+		_, err := DoWith(context.Background(), translator, TranslateRequest{Text: "Hello", To: params.GERMAN, Formality: formality})
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), stub.calls)
+}
+
+// funcTranslator adapts a function to the Translator interface in tests.
+type funcTranslator func(ctx context.Context, text, from, to string) (*Translated, error)
+
+func (f funcTranslator) Translate(ctx context.Context, text, from, to string) (*Translated, error) {
+	return f(ctx, text, from, to)
+}
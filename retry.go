@@ -0,0 +1,177 @@
+package gt
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"gopkg.gilang.dev/translator/v2/errs"
+)
+
+const (
+	DefaultRetryMaxAttempts = 3
+	DefaultRetryBaseDelay   = 500 * time.Millisecond
+	DefaultRetryMaxDelay    = 10 * time.Second
+	DefaultRetryJitter      = 0.2
+)
+
+// retryTranslator retries failed translations with exponential backoff.
+type retryTranslator struct {
+	translator  Translator
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	jitter      float64
+	retryIf     func(error) bool
+}
+
+// RetryOption is a functional option for configuring NewRetryTranslator.
+type RetryOption func(*retryTranslator)
+
+// WithMaxAttempts sets the maximum number of attempts, including the first one.
+func WithMaxAttempts(n int) RetryOption {
+	return func(r *retryTranslator) {
+		r.maxAttempts = n
+	}
+}
+
+// WithBaseDelay sets the delay before the first retry. It doubles on every retry.
+func WithBaseDelay(d time.Duration) RetryOption {
+	return func(r *retryTranslator) {
+		r.baseDelay = d
+	}
+}
+
+// WithMaxDelay caps the delay between two attempts.
+func WithMaxDelay(d time.Duration) RetryOption {
+	return func(r *retryTranslator) {
+		r.maxDelay = d
+	}
+}
+
+// WithJitter sets the random fraction (0 to 1) added to or removed from each delay.
+func WithJitter(fraction float64) RetryOption {
+	return func(r *retryTranslator) {
+		r.jitter = fraction
+	}
+}
+
+// WithRetryIf sets the predicate deciding whether an error is retried.
+func WithRetryIf(retryIf func(error) bool) RetryOption {
+	return func(r *retryTranslator) {
+		r.retryIf = retryIf
+	}
+}
+
+// NewRetryTranslator creates a Translator that retries translator on retryable
+// errors (see IsRetryable) with exponential backoff and jitter. A Retry-After
+// delay reported by the backend takes precedence over the computed delay.
+// Retrying stops early when ctx is done or its deadline is too close.
+func NewRetryTranslator(translator Translator, opts ...RetryOption) Translator {
+	r := &retryTranslator{
+		translator:  translator,
+		maxAttempts: DefaultRetryMaxAttempts,
+		baseDelay:   DefaultRetryBaseDelay,
+		maxDelay:    DefaultRetryMaxDelay,
+		jitter:      DefaultRetryJitter,
+		retryIf:     IsRetryable,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.maxAttempts < 1 {
+		r.maxAttempts = 1
+	}
+	return r
+}
+
+func (r *retryTranslator) Translate(ctx context.Context, text, from, to string) (*Translated, error) {
+	for attempt := 0; ; attempt++ {
+		result, err := r.translator.Translate(ctx, text, from, to)
+		if err == nil || attempt+1 >= r.maxAttempts || !r.retryIf(err) {
+			return result, err
+		}
+		if !r.wait(ctx, attempt, err) {
+			return nil, err
+		}
+	}
+}
+
+// TranslateBatch implements BatchTranslator, retrying only the texts that failed.
+func (r *retryTranslator) TranslateBatch(ctx context.Context, texts []string, from, to string) ([]BatchResult, error) {
+	results := make([]BatchResult, len(texts))
+	pending := make([]int, len(texts))
+	for i := range texts {
+		pending[i] = i
+	}
+
+	for attempt := 0; ; attempt++ {
+		batch := make([]string, len(pending))
+		for n, i := range pending {
+			batch[n] = texts[i]
+		}
+
+		batchResults, err := batchWith(ctx, r.translator, batch, from, to)
+		var (
+			next    []int
+			lastErr error
+		)
+		for n, i := range pending {
+			if err != nil {
+				results[i] = BatchResult{Err: err}
+			} else {
+				results[i] = batchResults[n]
+			}
+			if results[i].Err != nil && r.retryIf(results[i].Err) {
+				next = append(next, i)
+				lastErr = results[i].Err
+			}
+		}
+
+		pending = next
+		if len(pending) == 0 || attempt+1 >= r.maxAttempts || !r.wait(ctx, attempt, lastErr) {
+			return results, nil
+		}
+	}
+}
+
+// wait sleeps before the next attempt. It returns false if ctx is done or
+// would expire before the delay elapses.
+func (r *retryTranslator) wait(ctx context.Context, attempt int, err error) bool {
+	delay := r.delay(attempt, err)
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return false
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// delay computes the backoff before retrying after the given attempt.
+func (r *retryTranslator) delay(attempt int, err error) time.Duration {
+	var e *errs.Error
+	if errors.As(err, &e) && e.RetryAfter > 0 {
+		return e.RetryAfter
+	}
+
+	delay := r.baseDelay
+	for i := 0; i < attempt && delay < r.maxDelay; i++ {
+		delay *= 2
+	}
+	if r.maxDelay > 0 && delay > r.maxDelay {
+		delay = r.maxDelay
+	}
+	if r.jitter > 0 {
+		delay += time.Duration(float64(delay) * r.jitter * (2*rand.Float64() - 1))
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	return delay
+}
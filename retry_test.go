@@ -0,0 +1,93 @@
+package gt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.gilang.dev/translator/v2/errs"
+)
+
+func TestRetryTranslator(t *testing.T) {
+	stub := &stubTranslator{}
+	stub.fn = func(text, from, to string) (*Translated, error) {
+		if stub.calls < 3 {
+			return nil, errs.New(ErrNetwork, "stub", 0, "", nil)
+		}
+		return &Translated{Text: "halo"}, nil
+	}
+
+	translator := NewRetryTranslator(stub, WithMaxAttempts(5), WithBaseDelay(time.Millisecond), WithJitter(0))
+	result, err := translator.Translate(context.Background(), "hello", "en", "id")
+	assert.NoError(t, err)
+	assert.Equal(t, "halo", result.Text)
+	assert.Equal(t, int32(3), stub.calls)
+}
+
+func TestRetryTranslatorGivesUp(t *testing.T) {
+	stub := &stubTranslator{fn: func(text, from, to string) (*Translated, error) {
+		return nil, errs.FromStatus("stub", 429, "")
+	}}
+
+	translator := NewRetryTranslator(stub, WithMaxAttempts(2), WithBaseDelay(time.Millisecond))
+	_, err := translator.Translate(context.Background(), "hello", "en", "id")
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Equal(t, int32(2), stub.calls)
+}
+
+func TestRetryTranslatorNotRetryable(t *testing.T) {
+	stub := &stubTranslator{fn: func(text, from, to string) (*Translated, error) {
+		return nil, errs.New(ErrInvalidLanguage, "stub", 0, "", nil)
+	}}
+
+	_, err := NewRetryTranslator(stub).Translate(context.Background(), "hello", "en", "id")
+	assert.ErrorIs(t, err, ErrInvalidLanguage)
+	assert.Equal(t, int32(1), stub.calls)
+}
+
+func TestRetryTranslatorDeadline(t *testing.T) {
+	stub := &stubTranslator{fn: func(text, from, to string) (*Translated, error) {
+		e := errs.FromStatus("stub", 429, "")
+		e.RetryAfter = time.Minute
+		return nil, e
+	}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := NewRetryTranslator(stub).Translate(ctx, "hello", "en", "id")
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Equal(t, int32(1), stub.calls)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestRetryTranslatorDelay(t *testing.T) {
+	r := NewRetryTranslator(&stubTranslator{}, WithBaseDelay(100*time.Millisecond), WithMaxDelay(time.Second), WithJitter(0)).(*retryTranslator)
+	assert.Equal(t, 100*time.Millisecond, r.delay(0, nil))
+	assert.Equal(t, 400*time.Millisecond, r.delay(2, nil))
+	assert.Equal(t, time.Second, r.delay(10, nil))
+
+	e := errs.FromStatus("stub", 429, "")
+	e.RetryAfter = 3 * time.Second
+	assert.Equal(t, 3*time.Second, r.delay(0, e))
+}
+
+func TestRetryTranslatorBatch(t *testing.T) {
+	stub := &stubTranslator{}
+	failed := map[string]bool{}
+	stub.fn = func(text, from, to string) (*Translated, error) {
+		if text == "flaky" && !failed[text] {
+			failed[text] = true
+			return nil, errs.New(ErrNetwork, "stub", 0, "", nil)
+		}
+		return &Translated{Text: text}, nil
+	}
+
+	translator := NewRetryTranslator(stub, WithBaseDelay(time.Millisecond))
+	results, err := translator.(BatchTranslator).TranslateBatch(context.Background(), []string{"flaky"}, "en", "id")
+	assert.NoError(t, err)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, int32(2), stub.calls)
+}
@@ -0,0 +1,145 @@
+// Package subtitle reads, writes and translates SubRip (SRT) and WebVTT
+// subtitles, keeping their timing, cue identifiers, styling and line breaks.
+package subtitle
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+)
+
+// Format is a subtitle format.
+type Format string
+
+// Supported formats.
+const (
+	SRT    Format = "srt"
+	WebVTT Format = "vtt"
+)
+
+const bom = "\uFEFF"
+
+// File is a subtitle file.
+type File struct {
+	Format Format
+	Cues   []*Cue
+
+	blocks []block
+	crlf   bool // Whether lines end with "\r\n"
+	bom    bool // Whether the file starts with a byte order mark
+}
+
+// Cue is a subtitle shown during a time range.
+type Cue struct {
+	ID     string   // Identifier: the sequence number in SRT, optional in WebVTT
+	Timing string   // Timing line, such as "00:00:01,000 --> 00:00:02,500", with WebVTT settings
+	Lines  []string // Text lines, with their styling tags
+}
+
+// block is a block of a file: a cue, or lines kept as is, such as the
+// WebVTT header, NOTE, STYLE and REGION blocks.
+type block struct {
+	cue   *Cue
+	lines []string
+}
+
+// Parse parses SubRip or WebVTT subtitles, telling them apart by the WEBVTT
+// signature of the latter.
+func Parse(data []byte) (*File, error) {
+	text := string(data)
+	f := &File{Format: SRT}
+	if strings.HasPrefix(text, bom) {
+		f.bom = true
+		text = text[len(bom):]
+	}
+	f.crlf = strings.Contains(text, "\r\n")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if strings.HasPrefix(text, "WEBVTT") {
+		f.Format = WebVTT
+	}
+
+	for i, lines := range splitBlocks(text) {
+		switch {
+		case f.Format == WebVTT && (i == 0 || isMetadata(lines[0])):
+			f.blocks = append(f.blocks, block{lines: lines})
+		case strings.Contains(lines[0], "-->"):
+			f.addCue(&Cue{Timing: lines[0], Lines: lines[1:]})
+		case len(lines) > 1 && strings.Contains(lines[1], "-->"):
+			f.addCue(&Cue{ID: lines[0], Timing: lines[1], Lines: lines[2:]})
+		case f.Format == SRT:
+			return nil, errors.New("subtitle: invalid SRT block: " + lines[0])
+		default:
+			f.blocks = append(f.blocks, block{lines: lines})
+		}
+	}
+	if len(f.Cues) == 0 && f.Format == SRT {
+		return nil, errors.New("subtitle: no cue found")
+	}
+	return f, nil
+}
+
+func (f *File) addCue(cue *Cue) {
+	f.Cues = append(f.Cues, cue)
+	f.blocks = append(f.blocks, block{cue: cue})
+}
+
+// splitBlocks splits text into blocks of non-blank lines.
+func splitBlocks(text string) [][]string {
+	var (
+		blocks  [][]string
+		current []string
+	)
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			if current != nil {
+				blocks = append(blocks, current)
+				current = nil
+			}
+			continue
+		}
+		current = append(current, line)
+	}
+	if current != nil {
+		blocks = append(blocks, current)
+	}
+	return blocks
+}
+
+// isMetadata tells whether a WebVTT block starting with line isn't a cue.
+func isMetadata(line string) bool {
+	for _, keyword := range []string{"NOTE", "STYLE", "REGION"} {
+		if line == keyword || strings.HasPrefix(line, keyword+" ") || strings.HasPrefix(line, keyword+"\t") {
+			return true
+		}
+	}
+	return false
+}
+
+// Bytes returns f in its format, with the line endings and byte order mark
+// of the parsed file. Blocks are separated by a single blank line.
+func (f *File) Bytes() []byte {
+	var b bytes.Buffer
+	if f.bom {
+		b.WriteString(bom)
+	}
+	newline := "\n"
+	if f.crlf {
+		newline = "\r\n"
+	}
+	for i, block := range f.blocks {
+		if i > 0 {
+			b.WriteString(newline)
+		}
+		lines := block.lines
+		if cue := block.cue; cue != nil {
+			lines = append([]string{cue.Timing}, cue.Lines...)
+			if cue.ID != "" {
+				lines = append([]string{cue.ID}, lines...)
+			}
+		}
+		for _, line := range lines {
+			b.WriteString(line + newline)
+		}
+	}
+	return b.Bytes()
+}
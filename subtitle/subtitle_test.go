@@ -0,0 +1,79 @@
+package subtitle
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const srt = `1
+00:00:01,000 --> 00:00:03,500
+<i>Hello there,</i>
+my friend.
+
+2
+00:00:04,000 --> 00:00:06,000 X1:100 X2:200 Y1:100 Y2:200
+- Who are you?
+- Nobody.
+`
+
+const vtt = `WEBVTT - Demo
+Kind: captions
+
+NOTE This is a comment
+
+STYLE
+::cue { color: yellow }
+
+intro
+00:00:01.000 --> 00:00:03.000 align:start
+<v Alice>Welcome to the show
+
+00:00:03.500 --> 00:00:05.000
+that we made &amp; love.
+`
+
+func TestParseSRT(t *testing.T) {
+	f, err := Parse([]byte(srt))
+	require.NoError(t, err)
+	assert.Equal(t, SRT, f.Format)
+	require.Len(t, f.Cues, 2)
+	assert.Equal(t, "1", f.Cues[0].ID)
+	assert.Equal(t, "00:00:01,000 --> 00:00:03,500", f.Cues[0].Timing)
+	assert.Equal(t, []string{"<i>Hello there,</i>", "my friend."}, f.Cues[0].Lines)
+	assert.Equal(t, "00:00:04,000 --> 00:00:06,000 X1:100 X2:200 Y1:100 Y2:200", f.Cues[1].Timing)
+	assert.Equal(t, srt, string(f.Bytes()))
+}
+
+func TestParseWebVTT(t *testing.T) {
+	f, err := Parse([]byte(vtt))
+	require.NoError(t, err)
+	assert.Equal(t, WebVTT, f.Format)
+	require.Len(t, f.Cues, 2)
+	assert.Equal(t, "intro", f.Cues[0].ID)
+	assert.Equal(t, "00:00:01.000 --> 00:00:03.000 align:start", f.Cues[0].Timing)
+	assert.Empty(t, f.Cues[1].ID)
+	assert.Equal(t, vtt, string(f.Bytes()))
+}
+
+func TestParseLineEndings(t *testing.T) {
+	data := "\uFEFF" + strings.ReplaceAll(srt, "\n", "\r\n")
+	f, err := Parse([]byte(data))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"<i>Hello there,</i>", "my friend."}, f.Cues[0].Lines)
+	assert.Equal(t, data, string(f.Bytes()))
+
+	// Extra blank lines are collapsed
+	f, err = Parse([]byte("\n\n1\n00:00:01,000 --> 00:00:02,000\nHi\n\n\n\n2\n00:00:03,000 --> 00:00:04,000\nBye\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "1\n00:00:01,000 --> 00:00:02,000\nHi\n\n2\n00:00:03,000 --> 00:00:04,000\nBye\n", string(f.Bytes()))
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, data := range []string{"", "just text\n", "1\nnot a timing\nHi\n"} {
+		_, err := Parse([]byte(data))
+		assert.Error(t, err, data)
+	}
+}
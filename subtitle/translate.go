@@ -0,0 +1,327 @@
+package subtitle
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	gt "gopkg.gilang.dev/translator/v2"
+	"gopkg.gilang.dev/translator/v2/internal/placeholder"
+)
+
+// DefaultGroupSize is the largest number of cues translated together when a
+// sentence spans them.
+const DefaultGroupSize = 5
+
+var (
+	// styling matches tags, such as <i>, <font color="red">, WebVTT <v Name>,
+	// <c.class> and timestamps, ASS overrides such as {\an8}, and entities.
+	styling = regexp.MustCompile(`<[^<>\n]+>|\{\\[^{}\n]*\}|&(?:[A-Za-z]+|#[0-9]+|#[xX][0-9A-Fa-f]+);`)
+	// sentenceEnd matches the end of a cue ending a sentence.
+	sentenceEnd = regexp.MustCompile(`[.!?…。！？♪:;]["'”’»)\]]*$`)
+)
+
+// Translator translates subtitles with a gt.Translator.
+type Translator struct {
+	translator gt.Translator
+	groupSize  int
+}
+
+// Option is a functional option for configuring New.
+type Option func(*Translator)
+
+// WithGroupSize sets the largest number of cues translated together when a
+// sentence spans them, DefaultGroupSize by default. Use 1 to translate every
+// cue on its own.
+func WithGroupSize(n int) Option {
+	return func(t *Translator) {
+		t.groupSize = n
+	}
+}
+
+// New creates a Translator of subtitles using translator.
+func New(translator gt.Translator, opts ...Option) *Translator {
+	t := &Translator{
+		translator: translator,
+		groupSize:  DefaultGroupSize,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// unit is a text of a cue translated as a whole: all its lines, or a single
+// line of a dialogue cue whose lines all start with a dash.
+type unit struct {
+	cue    *Cue
+	line   int // Line of a dialogue cue, or -1 for the whole cue
+	text   string
+	tokens []string
+}
+
+// Translate translates the text of the cues of f, keeping their identifiers,
+// timing and styling tags. Cues continuing a sentence are translated
+// together, up to the group size, so that the sentence is translated as a
+// whole, and each cue gets its part of the translation; when the backend
+// doesn't keep the cue boundaries, the cues of the group are translated on
+// their own. The translated text of a cue is wrapped into as many lines as
+// it had, and each line of a dialogue cue is translated on its own. All cues
+// are translated in a single batch, plus one for the groups to retry.
+func (t *Translator) Translate(ctx context.Context, f *File, from, to string) error {
+	var units []*unit
+	for _, cue := range f.Cues {
+		if isDialogue(cue.Lines) {
+			for i, line := range cue.Lines {
+				units = appendUnit(units, cue, i, line)
+			}
+			continue
+		}
+		units = appendUnit(units, cue, -1, strings.Join(trimLines(cue.Lines), " "))
+	}
+	if len(units) == 0 {
+		return nil
+	}
+
+	groups := t.group(units)
+	texts := make([]string, len(groups))
+	for i, g := range groups {
+		texts[i] = g.text
+	}
+	results, err := translateAll(ctx, t.translator, texts, from, to)
+	if err != nil {
+		return err
+	}
+
+	translations := make(map[*unit]string, len(units))
+	var retry []*unit
+	for i, g := range groups {
+		parts, ok := g.split(results[i])
+		if !ok {
+			retry = append(retry, g.units...)
+			continue
+		}
+		for n, u := range g.units {
+			translations[u] = parts[n]
+		}
+	}
+	if len(retry) > 0 {
+		texts := make([]string, len(retry))
+		for i, u := range retry {
+			texts[i] = u.text
+		}
+		results, err := translateAll(ctx, t.translator, texts, from, to)
+		if err != nil {
+			return err
+		}
+		for i, u := range retry {
+			translations[u] = strings.TrimSpace(placeholder.Restore(results[i], u.tokens))
+		}
+	}
+
+	for _, u := range units {
+		translation := strings.Join(strings.Fields(translations[u]), " ")
+		if u.line >= 0 {
+			u.cue.Lines[u.line] = translation
+			continue
+		}
+		u.cue.Lines = wrap(translation, len(u.cue.Lines))
+	}
+	return nil
+}
+
+// appendUnit appends the text of a cue to units unless it has nothing to
+// translate.
+func appendUnit(units []*unit, cue *Cue, line int, text string) []*unit {
+	protected, tokens := placeholder.Protect(strings.TrimSpace(text), styling)
+	if placeholder.Empty(protected) {
+		return units
+	}
+	return append(units, &unit{cue: cue, line: line, text: protected, tokens: tokens})
+}
+
+// translateAll translates texts in a single batch and returns their
+// translations.
+func translateAll(ctx context.Context, translator gt.Translator, texts []string, from, to string) ([]string, error) {
+	results, err := gt.TranslateBatchWith(ctx, translator, texts, from, to)
+	if err != nil {
+		return nil, err
+	}
+	translations := make([]string, len(results))
+	for i, r := range results {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		translations[i] = r.Translated.Text
+	}
+	return translations, nil
+}
+
+// group is a group of units translated together, whose texts are separated
+// by placeholders.
+type group struct {
+	units      []*unit
+	text       string
+	tokens     []string
+	separators map[int]int // Placeholder of each separator to its rank
+}
+
+// group groups units continuing a sentence, up to the group size.
+func (t *Translator) group(units []*unit) []*group {
+	var (
+		groups  []*group
+		current *group
+	)
+	for _, u := range units {
+		if current == nil || u.line >= 0 || len(current.units) >= t.groupSize || current.ended() {
+			current = &group{separators: make(map[int]int)}
+			groups = append(groups, current)
+		}
+		current.add(u)
+	}
+	return groups
+}
+
+// ended tells whether no unit can join g, its last unit being a dialogue
+// line or ending a sentence.
+func (g *group) ended() bool {
+	last := g.units[len(g.units)-1]
+	return last.line >= 0 || sentenceEnd.MatchString(placeholder.Pattern.ReplaceAllString(last.text, ""))
+}
+
+// add adds u to g, renumbering its placeholders after those of g.
+func (g *group) add(u *unit) {
+	if len(g.units) > 0 {
+		g.separators[len(g.tokens)] = len(g.separators)
+		g.text += " " + placeholder.Token(len(g.tokens)) + " "
+		g.tokens = append(g.tokens, "")
+	}
+	offset := len(g.tokens)
+	g.text += placeholder.Pattern.ReplaceAllStringFunc(u.text, func(token string) string {
+		n, _ := strconv.Atoi(placeholder.Pattern.FindStringSubmatch(token)[1])
+		return placeholder.Token(offset + n)
+	})
+	g.tokens = append(g.tokens, u.tokens...)
+	g.units = append(g.units, u)
+}
+
+// split splits the translation of g into the translations of its units. It
+// fails unless every separator and tag is found once, in order for the
+// separators.
+func (g *group) split(translation string) ([]string, bool) {
+	var (
+		parts []string
+		seen  = make([]bool, len(g.tokens))
+		b     strings.Builder
+		last  int
+	)
+	for _, match := range placeholder.Pattern.FindAllStringSubmatchIndex(translation, -1) {
+		n, err := strconv.Atoi(translation[match[2]:match[3]])
+		if err != nil || n >= len(g.tokens) || seen[n] {
+			return nil, false
+		}
+		seen[n] = true
+		b.WriteString(translation[last:match[0]])
+		last = match[1]
+		if rank, ok := g.separators[n]; ok {
+			if rank != len(parts) {
+				return nil, false
+			}
+			parts = append(parts, strings.TrimSpace(b.String()))
+			b.Reset()
+			continue
+		}
+		b.WriteString(g.tokens[n])
+	}
+	for _, ok := range seen {
+		if !ok {
+			return nil, false
+		}
+	}
+	b.WriteString(translation[last:])
+	return append(parts, strings.TrimSpace(b.String())), true
+}
+
+// isDialogue tells whether lines are the lines of several speakers, each
+// starting with a dash.
+func isDialogue(lines []string) bool {
+	if len(lines) < 2 {
+		return false
+	}
+	for _, line := range lines {
+		if !strings.HasPrefix(strings.TrimSpace(styling.ReplaceAllString(line, "")), "-") {
+			return false
+		}
+	}
+	return true
+}
+
+func trimLines(lines []string) []string {
+	trimmed := make([]string, len(lines))
+	for i, line := range lines {
+		trimmed[i] = strings.TrimSpace(line)
+	}
+	return trimmed
+}
+
+// wrap wraps text into n lines of balanced lengths, breaking between words,
+// or between characters for texts without spaces. Styling tags don't count
+// in the lengths.
+func wrap(text string, n int) []string {
+	if n <= 1 {
+		return []string{text}
+	}
+	words := strings.Fields(text)
+	separator := " "
+	if len(words) < n {
+		words, separator = characters(text), ""
+		if len(words) < n {
+			return []string{text}
+		}
+	}
+
+	total := 0
+	for _, word := range words {
+		total += length(word)
+	}
+	var (
+		lines   []string
+		current []string
+		width   int
+	)
+	for i, word := range words {
+		// Break before the word when the line is closer to its share
+		// without it, keeping a word for each remaining line
+		share := total * (len(lines) + 1) / n
+		remaining := len(words) - i
+		if len(current) > 0 && len(lines) < n-1 &&
+			(remaining <= n-1-len(lines) || width+length(word)-share > share-width) {
+			lines = append(lines, strings.Join(current, separator))
+			current = nil
+		}
+		current = append(current, word)
+		width += length(word)
+	}
+	return append(lines, strings.Join(current, separator))
+}
+
+// characters splits text into characters, keeping styling tags whole.
+func characters(text string) []string {
+	var (
+		chars []string
+		last  int
+	)
+	for _, match := range styling.FindAllStringIndex(text, -1) {
+		chars = append(chars, strings.Split(text[last:match[0]], "")...)
+		chars = append(chars, text[match[0]:match[1]])
+		last = match[1]
+	}
+	return append(chars, strings.Split(text[last:], "")...)
+}
+
+// length returns the number of visible characters of s.
+func length(s string) int {
+	return utf8.RuneCountInString(styling.ReplaceAllString(s, ""))
+}
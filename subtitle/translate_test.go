@@ -0,0 +1,86 @@
+package subtitle
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.gilang.dev/translator/v2/internal/placeholder"
+	"gopkg.gilang.dev/translator/v2/internal/translatortest"
+)
+
+// dropPlaceholders removes the placeholders of text, like backends losing the
+// boundaries of grouped cues.
+func dropPlaceholders(text string) string {
+	return placeholder.Pattern.ReplaceAllString(text, "")
+}
+
+func TestTranslateSRT(t *testing.T) {
+	f, err := Parse([]byte(srt))
+	require.NoError(t, err)
+	u := &translatortest.Upper{}
+	require.NoError(t, New(u).Translate(context.Background(), f, "en", "id"))
+
+	want := `1
+00:00:01,000 --> 00:00:03,500
+<i>HELLO THERE,</i>
+MY FRIEND.
+
+2
+00:00:04,000 --> 00:00:06,000 X1:100 X2:200 Y1:100 Y2:200
+- WHO ARE YOU?
+- NOBODY.
+`
+	assert.Equal(t, want, string(f.Bytes()))
+	assert.ElementsMatch(t, []string{"⟦0⟧Hello there,⟦1⟧ my friend.", "- Who are you?", "- Nobody."}, u.Texts())
+}
+
+func TestTranslateWebVTTGroups(t *testing.T) {
+	f, err := Parse([]byte(vtt))
+	require.NoError(t, err)
+	u := &translatortest.Upper{}
+	require.NoError(t, New(u).Translate(context.Background(), f, "en", "id"))
+
+	// The sentence spanning both cues is translated at once
+	assert.Equal(t, []string{"⟦0⟧Welcome to the show ⟦1⟧ that we made ⟦2⟧ love."}, u.Texts())
+	assert.Equal(t, []string{"<v Alice>WELCOME TO THE SHOW"}, f.Cues[0].Lines)
+	assert.Equal(t, []string{"THAT WE MADE &amp; LOVE."}, f.Cues[1].Lines)
+	assert.Contains(t, string(f.Bytes()), "NOTE This is a comment\n\nSTYLE\n::cue { color: yellow }\n")
+}
+
+func TestTranslateRetry(t *testing.T) {
+	f, err := Parse([]byte(vtt))
+	require.NoError(t, err)
+	u := &translatortest.Upper{Rewrite: dropPlaceholders}
+	require.NoError(t, New(u).Translate(context.Background(), f, "en", "id"))
+
+	// Cue boundaries were lost, so cues are translated one by one
+	assert.Len(t, u.Texts(), 3)
+	assert.Equal(t, []string{"WELCOME TO THE SHOW <v Alice>"}, f.Cues[0].Lines)
+	assert.Equal(t, []string{"THAT WE MADE LOVE. &amp;"}, f.Cues[1].Lines)
+
+	f, err = Parse([]byte(vtt))
+	require.NoError(t, err)
+	u = &translatortest.Upper{}
+	require.NoError(t, New(u, WithGroupSize(1)).Translate(context.Background(), f, "en", "id"))
+	assert.Len(t, u.Texts(), 2)
+}
+
+func TestTranslateError(t *testing.T) {
+	f, err := Parse([]byte(srt))
+	require.NoError(t, err)
+	u := &translatortest.Upper{Err: errors.New("backend down")}
+	assert.ErrorIs(t, New(u).Translate(context.Background(), f, "en", "id"), u.Err)
+	assert.Equal(t, "my friend.", f.Cues[0].Lines[1])
+}
+
+func TestWrap(t *testing.T) {
+	assert.Equal(t, []string{"one two three"}, wrap("one two three", 1))
+	assert.Equal(t, []string{"a longer first", "line and more"}, wrap("a longer first line and more", 2))
+	assert.Equal(t, []string{"<i>word</i>", "next"}, wrap("<i>word</i> next", 2))
+	assert.Equal(t, []string{"one", "two", "three"}, wrap("one two three", 3))
+	assert.Equal(t, []string{"こんに", "ち<b>は世界"}, wrap("こんにち<b>は世界", 2))
+	assert.Equal(t, []string{"ab"}, wrap("ab", 3))
+}
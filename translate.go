@@ -2,8 +2,8 @@ package gt
 
 import (
 	"context"
-	"fmt"
 	"sync"
+	"unicode/utf8"
 
 	"golang.org/x/text/language"
 	"gopkg.gilang.dev/translator/v2/deepl"
@@ -23,6 +23,7 @@ type Translated struct {
 	Alternatives  []string      `json:"alternatives,omitempty"`
 	From          TranslateFrom `json:"from"`
 	Method        string        `json:"method,omitempty"`
+	Backend       string        `json:"backend,omitempty"`
 }
 
 // TranslateFrom contains source language and text information.
@@ -58,6 +59,13 @@ type googleTranslateAdapter struct {
 }
 
 func (g *googleTranslateAdapter) Translate(ctx context.Context, text, from, to string) (*Translated, error) {
+	if maxChars := g.client.MaxChars(); maxChars > 0 && utf8.RuneCountInString(text) > maxChars {
+		return translateChunks(ctx, g, splitText(text, maxChars), from, to, DefaultChunkConcurrency)
+	}
+	from, to, err := languagePair(Google, from, to)
+	if err != nil {
+		return nil, err
+	}
 	result, err := g.client.Translate(ctx, text, from, to)
 	if err != nil {
 		return nil, err
@@ -65,6 +73,7 @@ func (g *googleTranslateAdapter) Translate(ctx context.Context, text, from, to s
 	return &Translated{
 		Text:          result.Text,
 		Pronunciation: result.Pronunciation,
+		Backend:       googletranslate.BackendName,
 		From: TranslateFrom{
 			Language: TranslateFromLanguage{
 				DidYouMean: result.From.Language.DidYouMean,
@@ -85,10 +94,23 @@ type deeplAdapter struct {
 }
 
 func (d *deeplAdapter) Translate(ctx context.Context, text, from, to string) (*Translated, error) {
-	result, err := d.client.Translate(ctx, text, from, to)
+	if maxChars := d.client.MaxChars(); maxChars > 0 && utf8.RuneCountInString(text) > maxChars {
+		// Parallel requests quickly get rate limited by DeepL
+		return translateChunks(ctx, d, splitText(text, maxChars), from, to, 1)
+	}
+	from, to, err := languagePair(DeepL, from, to)
 	if err != nil {
 		return nil, err
 	}
+	result, err := d.client.TranslateWithOptions(ctx, text, from, to, deeplOptions(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return fromDeepL(result), nil
+}
+
+// fromDeepL converts a deepl.Translated into a Translated.
+func fromDeepL(result *deepl.Translated) *Translated {
 	return &Translated{
 		Text:         result.Text,
 		Alternatives: result.Alternatives,
@@ -97,8 +119,9 @@ func (d *deeplAdapter) Translate(ctx context.Context, text, from, to string) (*T
 				Iso: result.From.Language.Iso,
 			},
 		},
-		Method: result.Method,
-	}, nil
+		Method:  result.Method,
+		Backend: deepl.BackendName,
+	}
 }
 
 var (
@@ -150,43 +173,19 @@ func getTranslator() Translator {
 
 // TranslateWithParam translates text using parameters struct.
 func TranslateWithParam(ctx context.Context, value params.Translate) (*Translated, error) {
-	var (
-		text string
-		from = "auto"
-		to   string
-	)
-	if value.Text == "" {
-		return nil, fmt.Errorf("Text Value is required!")
-	}
-	text = value.Text
-
-	if value.To == "" {
-		return nil, fmt.Errorf("To Value is required!")
-	}
-	if _, err := language.Parse(value.To); err != nil {
-		return nil, fmt.Errorf("To Value isn't valid!")
-	}
-	to = value.To
-
-	if value.From != "" {
-		if _, err := language.Parse(value.From); err != nil {
-			return nil, fmt.Errorf("From Value isn't valid!")
-		}
-		from = value.From
-	}
-	return getTranslator().Translate(ctx, text, from, to)
+	return Do(ctx, value)
 }
 
 // Translate translates text with auto-detected source language.
 func Translate(ctx context.Context, text, toLanguage string) (*Translated, error) {
 	if text == "" {
-		return nil, fmt.Errorf("Text Value is required!")
+		return nil, errTextRequired()
 	}
 	if toLanguage == "" {
-		return nil, fmt.Errorf("To Value is required!")
+		return nil, errLanguageRequired("To")
 	}
 	if _, err := language.Parse(toLanguage); err != nil {
-		return nil, fmt.Errorf("To Value isn't valid!")
+		return nil, errLanguageInvalid("To", err)
 	}
 	return getTranslator().Translate(ctx, text, "auto", toLanguage)
 }
@@ -194,19 +193,19 @@ func Translate(ctx context.Context, text, toLanguage string) (*Translated, error
 // ManualTranslate translates text with explicit source and target languages.
 func ManualTranslate(ctx context.Context, text, fromLanguage, toLanguage string) (*Translated, error) {
 	if text == "" {
-		return nil, fmt.Errorf("Text Value is required!")
+		return nil, errTextRequired()
 	}
 	if fromLanguage == "" {
-		return nil, fmt.Errorf("From Value is required!")
+		return nil, errLanguageRequired("From")
 	}
 	if _, err := language.Parse(fromLanguage); err != nil {
-		return nil, fmt.Errorf("From Value isn't valid!")
+		return nil, errLanguageInvalid("From", err)
 	}
 	if toLanguage == "" {
-		return nil, fmt.Errorf("To Value is required!")
+		return nil, errLanguageRequired("To")
 	}
 	if _, err := language.Parse(toLanguage); err != nil {
-		return nil, fmt.Errorf("To Value isn't valid!")
+		return nil, errLanguageInvalid("To", err)
 	}
 	return getTranslator().Translate(ctx, text, fromLanguage, toLanguage)
 }
@@ -214,13 +213,13 @@ func ManualTranslate(ctx context.Context, text, fromLanguage, toLanguage string)
 // TranslateWith translates using a specific translator.
 func TranslateWith(ctx context.Context, translator Translator, text, toLanguage string) (*Translated, error) {
 	if text == "" {
-		return nil, fmt.Errorf("Text Value is required!")
+		return nil, errTextRequired()
 	}
 	if toLanguage == "" {
-		return nil, fmt.Errorf("To Value is required!")
+		return nil, errLanguageRequired("To")
 	}
 	if _, err := language.Parse(toLanguage); err != nil {
-		return nil, fmt.Errorf("To Value isn't valid!")
+		return nil, errLanguageInvalid("To", err)
 	}
 	return translator.Translate(ctx, text, "auto", toLanguage)
 }
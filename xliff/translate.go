@@ -0,0 +1,116 @@
+package xliff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	gt "gopkg.gilang.dev/translator/v2"
+	"gopkg.gilang.dev/translator/v2/internal/placeholder"
+)
+
+// ErrInlineCodes is returned by Translator.Translate when the backend lost or
+// reordered the inline codes of segments.
+var ErrInlineCodes = errors.New("xliff: inline codes lost in translation")
+
+// Translator translates XLIFF documents with a gt.Translator.
+type Translator struct {
+	translator gt.Translator
+	overwrite  bool
+}
+
+// Option is a functional option for configuring New.
+type Option func(*Translator)
+
+// WithOverwrite translates again the segments having a target, which are
+// kept by default.
+func WithOverwrite() Option {
+	return func(t *Translator) {
+		t.overwrite = true
+	}
+}
+
+// New creates a Translator of XLIFF documents using translator.
+func New(translator gt.Translator, opts ...Option) *Translator {
+	t := &Translator{translator: translator}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Translate translates the source of the segments of d without target, and
+// sets their target and state as SetTarget does. Inline elements, such as
+// <g>, <x/>, <ph>, <pc> or <mrk>, are kept where the backend places them,
+// and the content of code elements such as <ph> or <bpt> is never
+// translated. Segments marked translate="no" are skipped. When from is
+// "auto" or empty, the source language declared by d is used, and d gets
+// to as target language unless it declares one. All segments are translated
+// in a single batch.
+//
+// Segments whose translation lost, repeated or reordered the placeholders of
+// their inline codes are left untranslated, as their markup would no longer
+// be well-formed, and Translate returns an ErrInlineCodes error naming them
+// once the other segments are translated.
+func (t *Translator) Translate(ctx context.Context, d *Document, from, to string) error {
+	if (from == "" || from == "auto") && d.SourceLanguage != "" {
+		from = d.SourceLanguage
+	}
+	var (
+		segments []*Segment
+		texts    []string
+		indexes  = make(map[string]int)
+	)
+	for _, s := range d.Segments {
+		if !s.Translate || s.Target != "" && !t.overwrite || placeholder.Empty(s.text) {
+			continue
+		}
+		segments = append(segments, s)
+		if _, ok := indexes[key(s)]; !ok {
+			indexes[key(s)] = len(texts)
+			texts = append(texts, s.text)
+		}
+	}
+	if len(segments) == 0 {
+		d.setTargetLanguage(to)
+		return nil
+	}
+
+	results, err := gt.TranslateBatchWith(ctx, t.translator, texts, from, to)
+	if err != nil {
+		return err
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			return r.Err
+		}
+	}
+	var broken []string
+	for _, s := range segments {
+		text := results[indexes[key(s)]].Translated.Text
+		if !placeholder.Intact(text, len(s.tokens), s.pairs) {
+			broken = append(broken, s.ID)
+			continue
+		}
+		s.setTarget(placeholder.Pattern.ReplaceAllString(text, ""), placeholder.Restore(escapeText(text), s.tokens))
+	}
+	d.setTargetLanguage(to)
+	if len(broken) > 0 {
+		return fmt.Errorf("%w: segments %s left untranslated", ErrInlineCodes, strings.Join(broken, ", "))
+	}
+	return nil
+}
+
+// setTargetLanguage sets the target language of d unless it declares one.
+func (d *Document) setTargetLanguage(language string) {
+	if d.TargetLanguage == "" {
+		d.TargetLanguage = language
+	}
+}
+
+// key identifies the segments translated alike: same text and same inline
+// codes.
+func key(s *Segment) string {
+	return s.text + "\x00" + strings.Join(s.tokens, "\x00")
+}
@@ -0,0 +1,88 @@
+package xliff
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.gilang.dev/translator/v2/internal/translatortest"
+)
+
+func TestTranslate12(t *testing.T) {
+	d, err := Parse([]byte(v12))
+	require.NoError(t, err)
+	u := &translatortest.Upper{}
+	require.NoError(t, New(u).Translate(context.Background(), d, "auto", "id"))
+
+	assert.Equal(t, []string{"Hello ⟦0⟧dear & kind⟦1⟧ user⟦2⟧!"}, u.Texts())
+	from, _ := u.Languages()
+	assert.Equal(t, "en", from)
+	assert.Equal(t, "HELLO DEAR & KIND USER!", d.Segments[0].Target)
+	assert.Equal(t, StateNeedsReview, d.Segments[0].State)
+
+	want := strings.NewReplacer(
+		`datatype="html">`, `datatype="html" target-language="id">`,
+		`user<x id="2"/>!</source>`, `user<x id="2"/>!</source>
+        <target state="needs-review-translation">HELLO <g id="1">DEAR &amp; KIND</g> USER<x id="2"/>!</target>`,
+	).Replace(v12)
+	assert.Equal(t, want, string(d.Bytes()))
+}
+
+func TestTranslate20(t *testing.T) {
+	d, err := Parse([]byte(v20))
+	require.NoError(t, err)
+	require.NoError(t, New(&translatortest.Upper{}).Translate(context.Background(), d, "en", "fr"))
+
+	want := strings.NewReplacer(
+		`srcLang="en">`, `srcLang="en" trgLang="fr">`,
+		`      <segment>
+        <source>Open <pc id="1">the file</pc><ph id="2"/>.</source>`, `      <segment state="translated">
+        <source>Open <pc id="1">the file</pc><ph id="2"/>.</source>
+        <target>OPEN <pc id="1">THE FILE</pc><ph id="2"/>.</target>`,
+		`<segment state="initial">`, `<segment state="translated">`,
+		`<target></target>`, `<target>CLOSE IT.</target>`,
+	).Replace(v20)
+	assert.Equal(t, want, string(d.Bytes()))
+}
+
+func TestTranslateOverwrite(t *testing.T) {
+	d, err := Parse([]byte(v12))
+	require.NoError(t, err)
+	u := &translatortest.Upper{}
+	require.NoError(t, New(u, WithOverwrite()).Translate(context.Background(), d, "en", "id"))
+	assert.Len(t, u.Texts(), 2)
+	assert.Contains(t, string(d.Bytes()), `<target state="needs-review-translation">PRESS <ph id="1">&lt;b&gt;</ph>ENTER<ph id="2">&lt;/b&gt;</ph></target>`)
+}
+
+func TestTranslateError(t *testing.T) {
+	d, err := Parse([]byte(v12))
+	require.NoError(t, err)
+	u := &translatortest.Upper{Err: errors.New("backend down")}
+	assert.ErrorIs(t, New(u).Translate(context.Background(), d, "en", "id"), u.Err)
+	assert.Empty(t, d.Segments[0].Target)
+	assert.Empty(t, d.TargetLanguage)
+}
+
+func TestTranslateBrokenInlineCodes(t *testing.T) {
+	for name, rewrite := range map[string]func(string) string{
+		"dropped": func(text string) string { return strings.Replace(text, "⟦0⟧", "", 1) },
+		"swapped": strings.NewReplacer("⟦0⟧", "⟦1⟧", "⟦1⟧", "⟦0⟧").Replace,
+	} {
+		t.Run(name, func(t *testing.T) {
+			d, err := Parse([]byte(v20))
+			require.NoError(t, err)
+			err = New(&translatortest.Upper{Rewrite: rewrite}).Translate(context.Background(), d, "en", "fr")
+			assert.ErrorIs(t, err, ErrInlineCodes)
+			assert.ErrorContains(t, err, "u1")
+
+			assert.Empty(t, d.Segments[0].Target)
+			assert.Equal(t, "CLOSE IT.", d.Segments[1].Target)
+			assert.Equal(t, "fr", d.TargetLanguage)
+			_, err = Parse(d.Bytes())
+			assert.NoError(t, err)
+		})
+	}
+}
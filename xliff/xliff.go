@@ -0,0 +1,398 @@
+// Package xliff reads and writes XLIFF 1.2 and 2.0 documents, and translates
+// their source segments with any gt.Translator.
+//
+// Documents are edited in place: only the targets of the translated segments
+// and the target language attributes change, and the rest of the document is
+// written back byte for byte.
+package xliff
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.gilang.dev/translator/v2/internal/placeholder"
+)
+
+// States given to the segments translated with SetTarget or a Translator:
+// XLIFF 1.2 has a state for targets needing review, while XLIFF 2.0 only
+// tells that a segment is translated and not yet reviewed.
+const (
+	StateNeedsReview = "needs-review-translation" // XLIFF 1.2 target state
+	StateTranslated  = "translated"               // XLIFF 2.0 segment state
+)
+
+// codeElements are the inline elements whose content is native code rather
+// than text, and is never translated.
+var codeElements = map[string]bool{
+	"ph":  true,
+	"bpt": true,
+	"ept": true,
+	"it":  true,
+	"sub": true,
+}
+
+// Document is an XLIFF document.
+type Document struct {
+	Version        string // "1.2" or "2.0", as declared by the document
+	SourceLanguage string // source-language of the first file, or srcLang
+	TargetLanguage string // target-language of the first file, or trgLang
+	Segments       []*Segment
+
+	data           []byte
+	targetLanguage string // Target language declared by the document
+	languageTags   []span // Start tags declaring the target language
+}
+
+// Segment is a source segment of a document with its translation: a
+// <trans-unit> in XLIFF 1.2, a <segment> of a <unit> in XLIFF 2.0.
+type Segment struct {
+	ID        string // Id of the trans-unit or unit
+	Source    string // Source text, without inline codes
+	Target    string // Target text, without inline codes
+	State     string // State of the target in 1.2, of the segment in 2.0
+	Translate bool   // Whether the segment may be translated
+
+	text         string             // Source text with placeholders for its inline codes
+	tokens       []string           // Markup of the placeholders
+	pairs        []placeholder.Pair // Placeholders of start and end tags
+	source       span               // <source> element
+	target       *span              // <target> element, if any
+	segmentTag   span               // <segment> start tag in 2.0
+	v2           bool               // Whether the segment is an XLIFF 2.0 one
+	targetMarkup *string            // Content of the new target
+}
+
+// span is a byte range of the document.
+type span struct{ start, end int }
+
+// IsV2 tells whether d is an XLIFF 2 document.
+func (d *Document) IsV2() bool {
+	return strings.HasPrefix(d.Version, "2")
+}
+
+// SetTarget sets the target of s to text and marks it as needing review.
+func (s *Segment) SetTarget(text string) {
+	markup := escapeText(text)
+	s.setTarget(text, markup)
+}
+
+func (s *Segment) setTarget(text, markup string) {
+	s.Target = text
+	s.targetMarkup = &markup
+	if s.v2 {
+		s.State = StateTranslated
+	} else {
+		s.State = StateNeedsReview
+	}
+}
+
+// Parse parses an XLIFF 1.2 or 2.0 document.
+func Parse(data []byte) (*Document, error) {
+	d := &Document{data: data}
+	p := &parser{doc: d, dec: xml.NewDecoder(bytes.NewReader(data))}
+	if err := p.parse(); err != nil {
+		return nil, fmt.Errorf("xliff: %w", err)
+	}
+	if d.Version == "" {
+		return nil, errors.New("xliff: not an XLIFF document")
+	}
+	d.targetLanguage = d.TargetLanguage
+	return d, nil
+}
+
+// parser reads a document token by token, keeping track of the byte range
+// of each token.
+type parser struct {
+	doc   *Document
+	dec   *xml.Decoder
+	stack []frame
+
+	unit    *Segment // Trans-unit (1.2) or unit (2.0) being read
+	segment *Segment // Segment being read
+	content *content // Content of the source or target being read
+}
+
+// frame is an open element.
+type frame struct {
+	name      string
+	translate bool
+}
+
+// content is the content of a <source> or <target> element being read.
+type content struct {
+	target    bool
+	start     int
+	depth     int
+	plain     strings.Builder
+	text      strings.Builder
+	tokens    []string
+	pairs     []placeholder.Pair
+	open      []int           // Placeholders of the start tags not yet closed
+	code      strings.Builder // Markup of the code element being read
+	codeDepth int
+}
+
+func (p *parser) parse() error {
+	for {
+		start := int(p.dec.InputOffset())
+		token, err := p.dec.Token()
+		if err == io.EOF {
+			if len(p.stack) > 0 {
+				return errors.New("unexpected end of document")
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		s := span{start, int(p.dec.InputOffset())}
+		raw := string(p.doc.data[s.start:s.end])
+
+		if p.content != nil {
+			if err := p.readContent(token, raw, s); err != nil {
+				return err
+			}
+			continue
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			p.startElement(t, s)
+		case xml.EndElement:
+			p.endElement(t)
+		}
+	}
+}
+
+func (p *parser) startElement(e xml.StartElement, s span) {
+	translate := len(p.stack) == 0 || p.stack[len(p.stack)-1].translate
+	if value, ok := attr(e, "translate"); ok {
+		translate = value != "no"
+	}
+	parent := ""
+	if len(p.stack) > 0 {
+		parent = p.stack[len(p.stack)-1].name
+	}
+	p.stack = append(p.stack, frame{name: e.Name.Local, translate: translate})
+
+	d := p.doc
+	switch name := e.Name.Local; {
+	case name == "xliff" && parent == "":
+		d.Version, _ = attr(e, "version")
+		if d.IsV2() {
+			d.SourceLanguage, _ = attr(e, "srcLang")
+			d.TargetLanguage, _ = attr(e, "trgLang")
+			d.languageTags = append(d.languageTags, s)
+		}
+	case name == "file" && !d.IsV2():
+		if d.SourceLanguage == "" {
+			d.SourceLanguage, _ = attr(e, "source-language")
+		}
+		if d.TargetLanguage == "" {
+			d.TargetLanguage, _ = attr(e, "target-language")
+		}
+		d.languageTags = append(d.languageTags, s)
+	case name == "trans-unit" && !d.IsV2():
+		id, _ := attr(e, "id")
+		p.segment = &Segment{ID: id, Translate: translate}
+	case name == "unit" && d.IsV2():
+		id, _ := attr(e, "id")
+		p.unit = &Segment{ID: id, Translate: translate}
+	case name == "segment" && p.unit != nil:
+		state, _ := attr(e, "state")
+		p.segment = &Segment{ID: p.unit.ID, Translate: translate, State: state, segmentTag: s, v2: true}
+	case (name == "source" || name == "target") && p.segment != nil && (parent == "trans-unit" || parent == "segment"):
+		p.content = &content{target: name == "target", start: s.start, depth: 1}
+		if name == "target" && !d.IsV2() {
+			p.segment.State, _ = attr(e, "state")
+		}
+	}
+}
+
+func (p *parser) endElement(e xml.EndElement) {
+	p.stack = p.stack[:len(p.stack)-1]
+	switch e.Name.Local {
+	case "trans-unit", "segment":
+		if p.segment != nil {
+			p.doc.Segments = append(p.doc.Segments, p.segment)
+			p.segment = nil
+		}
+	case "unit":
+		p.unit = nil
+	}
+}
+
+// readContent reads a token of the source or target being read.
+func (p *parser) readContent(token xml.Token, raw string, s span) error {
+	c := p.content
+	switch t := token.(type) {
+	case xml.StartElement:
+		c.depth++
+		switch {
+		case c.codeDepth > 0:
+			c.code.WriteString(raw)
+			c.codeDepth++
+		case codeElements[t.Name.Local]:
+			c.code.WriteString(raw)
+			c.codeDepth = 1
+		default:
+			c.open = append(c.open, len(c.tokens))
+			c.protect(raw)
+		}
+	case xml.EndElement:
+		c.depth--
+		if c.depth == 0 {
+			p.endContent(span{c.start, s.end})
+			p.stack = p.stack[:len(p.stack)-1]
+			return nil
+		}
+		switch {
+		case c.codeDepth > 0:
+			c.code.WriteString(raw)
+			if c.codeDepth--; c.codeDepth == 0 {
+				c.protect(c.code.String())
+				c.code.Reset()
+			}
+		default:
+			open := c.open[len(c.open)-1]
+			c.open = c.open[:len(c.open)-1]
+			if raw != "" { // Not a self-closing tag
+				c.pairs = append(c.pairs, placeholder.Pair{Open: open, Close: len(c.tokens)})
+				c.protect(raw)
+			}
+		}
+	case xml.CharData:
+		if c.codeDepth > 0 {
+			c.code.WriteString(raw)
+			break
+		}
+		c.plain.Write(t)
+		c.text.Write(t)
+	default:
+		if c.codeDepth > 0 {
+			c.code.WriteString(raw)
+			break
+		}
+		c.protect(raw)
+	}
+	return nil
+}
+
+// protect replaces markup with a placeholder in the text to translate.
+func (c *content) protect(markup string) {
+	c.text.WriteString(placeholder.Token(len(c.tokens)))
+	c.tokens = append(c.tokens, markup)
+}
+
+// endContent ends the source or target element at s.
+func (p *parser) endContent(s span) {
+	c, segment := p.content, p.segment
+	p.content = nil
+	if c.target {
+		segment.Target = c.plain.String()
+		segment.target = &s
+		return
+	}
+	segment.Source = c.plain.String()
+	segment.text = c.text.String()
+	segment.tokens = c.tokens
+	segment.pairs = c.pairs
+	segment.source = s
+}
+
+// attr returns the value of the attribute name of e.
+func attr(e xml.StartElement, name string) (string, bool) {
+	for _, a := range e.Attr {
+		if a.Name.Local == name && a.Name.Space == "" {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+// Bytes returns d with the targets set since it was parsed.
+func (d *Document) Bytes() []byte {
+	type edit struct {
+		span
+		text string
+	}
+	var edits []edit
+	if d.TargetLanguage != d.targetLanguage {
+		name := "target-language"
+		if d.IsV2() {
+			name = "trgLang"
+		}
+		for _, tag := range d.languageTags {
+			edits = append(edits, edit{tag, setAttr(string(d.data[tag.start:tag.end]), name, d.TargetLanguage)})
+		}
+	}
+
+	for _, s := range d.Segments {
+		if s.targetMarkup == nil {
+			continue
+		}
+		target := "<target>" + *s.targetMarkup + "</target>"
+		if d.IsV2() {
+			edits = append(edits, edit{s.segmentTag, setAttr(string(d.data[s.segmentTag.start:s.segmentTag.end]), "state", s.State)})
+		} else {
+			target = `<target state="` + escapeAttr(s.State) + `">` + *s.targetMarkup + "</target>"
+		}
+		if s.target != nil {
+			edits = append(edits, edit{*s.target, target})
+			continue
+		}
+		edits = append(edits, edit{span{s.source.end, s.source.end}, d.indent(s.source.start) + target})
+	}
+
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+	var (
+		b    bytes.Buffer
+		last int
+	)
+	for _, e := range edits {
+		b.Write(d.data[last:e.start])
+		b.WriteString(e.text)
+		last = e.end
+	}
+	b.Write(d.data[last:])
+	return b.Bytes()
+}
+
+// indent returns a line break and the indentation of the element starting
+// at offset, or "" if it doesn't start its line.
+func (d *Document) indent(offset int) string {
+	i := offset
+	for i > 0 && (d.data[i-1] == ' ' || d.data[i-1] == '\t') {
+		i--
+	}
+	if i > 0 && d.data[i-1] != '\n' {
+		return ""
+	}
+	return "\n" + string(d.data[i:offset])
+}
+
+// setAttr sets the attribute name of the start tag to value.
+func setAttr(tag, name, value string) string {
+	re := regexp.MustCompile(`(\s` + regexp.QuoteMeta(name) + `\s*=\s*)(?:"[^"]*"|'[^']*')`)
+	if loc := re.FindStringSubmatchIndex(tag); loc != nil {
+		return tag[:loc[3]] + `"` + escapeAttr(value) + `"` + tag[loc[1]:]
+	}
+	end := len(tag) - 1
+	if strings.HasSuffix(tag, "/>") {
+		end--
+	}
+	return tag[:end] + " " + name + `="` + escapeAttr(value) + `"` + tag[end:]
+}
+
+var (
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;")
+)
+
+func escapeText(s string) string { return textEscaper.Replace(s) }
+func escapeAttr(s string) string { return attrEscaper.Replace(s) }
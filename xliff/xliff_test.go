@@ -0,0 +1,108 @@
+package xliff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const v12 = `<?xml version="1.0" encoding="UTF-8"?>
+<xliff version="1.2" xmlns="urn:oasis:names:tc:xliff:document:1.2">
+  <file original="app.html" source-language="en" datatype="html">
+    <body>
+      <trans-unit id="greeting">
+        <source>Hello <g id="1">dear &amp; kind</g> user<x id="2"/>!</source>
+      </trans-unit>
+      <trans-unit id="code">
+        <source>Press <ph id="1">&lt;b&gt;</ph>Enter<ph id="2">&lt;/b&gt;</ph></source>
+        <target state="translated">Tekan <ph id="1">&lt;b&gt;</ph>Enter<ph id="2">&lt;/b&gt;</ph></target>
+      </trans-unit>
+      <group translate="no">
+        <trans-unit id="brand"><source>Acme</source></trans-unit>
+      </group>
+    </body>
+  </file>
+</xliff>
+`
+
+const v20 = `<?xml version="1.0" encoding="UTF-8"?>
+<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="2.0" srcLang="en">
+  <file id="f1">
+    <unit id="u1">
+      <segment>
+        <source>Open <pc id="1">the file</pc><ph id="2"/>.</source>
+      </segment>
+      <ignorable>
+        <source> </source>
+      </ignorable>
+      <segment state="initial">
+        <source>Close it.</source>
+        <target></target>
+      </segment>
+    </unit>
+  </file>
+</xliff>
+`
+
+func TestParse12(t *testing.T) {
+	d, err := Parse([]byte(v12))
+	require.NoError(t, err)
+	assert.Equal(t, "1.2", d.Version)
+	assert.False(t, d.IsV2())
+	assert.Equal(t, "en", d.SourceLanguage)
+	assert.Empty(t, d.TargetLanguage)
+	require.Len(t, d.Segments, 3)
+
+	greeting := d.Segments[0]
+	assert.Equal(t, "greeting", greeting.ID)
+	assert.Equal(t, "Hello dear & kind user!", greeting.Source)
+	assert.Equal(t, "Hello ⟦0⟧dear & kind⟦1⟧ user⟦2⟧!", greeting.text)
+	assert.Equal(t, []string{`<g id="1">`, "</g>", `<x id="2"/>`}, greeting.tokens)
+	assert.Empty(t, greeting.Target)
+	assert.True(t, greeting.Translate)
+
+	code := d.Segments[1]
+	assert.Equal(t, "Press Enter", code.Source)
+	assert.Equal(t, "Tekan Enter", code.Target)
+	assert.Equal(t, "translated", code.State)
+
+	assert.False(t, d.Segments[2].Translate)
+	assert.Equal(t, v12, string(d.Bytes()))
+}
+
+func TestParse20(t *testing.T) {
+	d, err := Parse([]byte(v20))
+	require.NoError(t, err)
+	assert.True(t, d.IsV2())
+	assert.Equal(t, "en", d.SourceLanguage)
+	require.Len(t, d.Segments, 2)
+	assert.Equal(t, "u1", d.Segments[0].ID)
+	assert.Equal(t, "Open the file.", d.Segments[0].Source)
+	assert.Equal(t, "initial", d.Segments[1].State)
+	assert.Equal(t, v20, string(d.Bytes()))
+}
+
+func TestSetTarget(t *testing.T) {
+	d, err := Parse([]byte(v12))
+	require.NoError(t, err)
+	d.TargetLanguage = "id"
+	d.Segments[2].SetTarget("Acme & Co")
+
+	want := `<file original="app.html" source-language="en" datatype="html" target-language="id">`
+	assert.Contains(t, string(d.Bytes()), want)
+	assert.Contains(t, string(d.Bytes()), `<trans-unit id="brand"><source>Acme</source><target state="needs-review-translation">Acme &amp; Co</target></trans-unit>`)
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, data := range []string{``, `<html></html>`, `<xliff version="1.2"><file>`, `<xliff version="1.2"></file>`} {
+		_, err := Parse([]byte(data))
+		assert.Error(t, err, data)
+	}
+}
+
+func TestSetAttr(t *testing.T) {
+	assert.Equal(t, `<segment id="s" state="translated">`, setAttr(`<segment id="s" state='initial'>`, "state", "translated"))
+	assert.Equal(t, `<segment state="translated"/>`, setAttr(`<segment/>`, "state", "translated"))
+	assert.Equal(t, `<file substate="x" state="a&quot;b">`, setAttr(`<file substate="x">`, "state", `a"b`))
+}